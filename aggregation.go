@@ -0,0 +1,139 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultAggregationPollInterval = 5 * time.Second
+	defaultAggregationTimeout      = 3 * time.Minute
+)
+
+// AggregationOutcome is the final state of an aggregation started by AggregateAndWait
+type AggregationOutcome string
+
+const (
+	AggregationSucceeded AggregationOutcome = "succeeded"
+	AggregationFailed    AggregationOutcome = "failed"
+	AggregationTimedOut  AggregationOutcome = "timed_out"
+	// AggregationSkipped means no aggregation was triggered, eg because it requires user operation
+	AggregationSkipped AggregationOutcome = "skipped"
+)
+
+// AggregateOptions configures AggregateAndWait
+type AggregateOptions struct {
+	// PollInterval is the time between two status checks, defaults to 5 seconds
+	PollInterval time.Duration
+	// Timeout is the maximum time to wait for the aggregation, defaults to 3 minutes
+	Timeout time.Duration
+}
+
+// AggregationResult describes the outcome of an aggregation
+type AggregationResult struct {
+	AccountIDHash string
+	Name          string
+	Outcome       AggregationOutcome
+	// Status and ErrorID are the account's values at the last poll
+	Status  AccountStatus
+	ErrorID AggregationErrorID
+	// PreviousAggregatedAt and LastAggregatedAt are the aggregation times before and after the trigger
+	PreviousAggregatedAt Timestamp
+	LastAggregatedAt     Timestamp
+	// LastSucceededAt is the time of the last successful aggregation at the last poll
+	LastSucceededAt Timestamp
+	Duration        time.Duration
+}
+
+// ErrAccountNotFound is returned when an account ID hash is not part of the account summaries
+var ErrAccountNotFound = errors.New("moneyforward: account not found")
+
+// AggregateAndWait triggers an aggregation of an account and polls the account summaries until
+// LastAggregatedAt advances or a new error is reported.
+// The aggregation only counts as succeeded if LastSucceededAt advanced as well, otherwise it is
+// reported as AggregationFailed even when no error ID is set.
+// A timeout is reported as AggregationTimedOut in the result, not as an error.
+func (c *Client) AggregateAndWait(ctx context.Context, accountIDHash string, aggOpts AggregateOptions, opts ...RequestOption) (*AggregationResult, error) {
+	pollInterval := aggOpts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultAggregationPollInterval
+	}
+	timeout := aggOpts.Timeout
+	if timeout <= 0 {
+		timeout = defaultAggregationTimeout
+	}
+
+	before, err := c.accountSummary(ctx, accountIDHash, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := c.TriggerAccountAggregation(ctx, accountIDHash, opts...); err != nil {
+		return nil, fmt.Errorf("failed to trigger aggregation: %w", err)
+	}
+
+	result := &AggregationResult{
+		AccountIDHash:        accountIDHash,
+		Name:                 before.Name,
+		PreviousAggregatedAt: before.LastAggregatedAt,
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			result.Outcome = AggregationTimedOut
+			result.Duration = time.Since(start)
+			return result, nil
+		case <-ticker.C:
+		}
+
+		account, err := c.accountSummary(ctx, accountIDHash, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Status = account.Status
+		result.ErrorID = account.ErrorID
+		result.LastAggregatedAt = account.LastAggregatedAt
+		result.LastSucceededAt = account.LastSucceededAt
+
+		advanced := account.LastAggregatedAt.After(before.LastAggregatedAt.Time)
+		newError := account.ErrorID.IsError() && account.ErrorID != before.ErrorID
+		if !advanced && !newError {
+			continue
+		}
+
+		result.Outcome = AggregationFailed
+		if !account.ErrorID.IsError() && account.LastSucceededAt.After(before.LastSucceededAt.Time) {
+			result.Outcome = AggregationSucceeded
+		}
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+}
+
+// accountSummary gets the summary of a single account
+func (c *Client) accountSummary(ctx context.Context, accountIDHash string, opts ...RequestOption) (*AccountSummary, error) {
+	summaries, err := c.GetAccountSummaries(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range summaries.Accounts {
+		if summaries.Accounts[i].AccountIDHash == accountIDHash {
+			return &summaries.Accounts[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, accountIDHash)
+}
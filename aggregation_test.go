@@ -0,0 +1,156 @@
+package moneyforward
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAccountState is the aggregation state of a fake account as seen in the account summaries
+type fakeAccountState struct {
+	aggregatedAt, succeededAt string
+	errorID                   int
+}
+
+// fakeAccount is a linked account served by aggregationServer
+type fakeAccount struct {
+	hash                  string
+	requiresUserOperation bool
+	// before is reported until the aggregation is triggered, after from then on.
+	// A nil after means the aggregation never finishes.
+	before fakeAccountState
+	after  *fakeAccountState
+	// triggerStatus is the status code of the trigger request, defaults to 200
+	triggerStatus int
+}
+
+// aggregationServer serves the account summaries, account details and aggregation triggers of accounts
+func aggregationServer(t *testing.T, accounts ...*fakeAccount) http.HandlerFunc {
+	var mu sync.Mutex
+	triggered := make(map[string]bool)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/account_summaries"):
+			var summaries []map[string]interface{}
+			for _, account := range accounts {
+				state := account.before
+				if triggered[account.hash] && account.after != nil {
+					state = *account.after
+				}
+				summaries = append(summaries, map[string]interface{}{
+					"account_id_hash":    account.hash,
+					"name":               "Account " + account.hash,
+					"show_path":          "/sp2/accounts/" + account.hash,
+					"last_aggregated_at": state.aggregatedAt,
+					"last_succeeded_at":  state.succeededAt,
+					"error_id":           state.errorID,
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"accounts": summaries})
+		case strings.HasSuffix(r.URL.Path, "/aggregation_queue"):
+			hash := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/accounts/")+len("/accounts/"):], "/aggregation_queue")
+			for _, account := range accounts {
+				if account.hash != hash {
+					continue
+				}
+				if account.triggerStatus != 0 && account.triggerStatus != http.StatusOK {
+					http.Error(w, `{"error":"busy"}`, account.triggerStatus)
+					return
+				}
+				triggered[hash] = true
+			}
+			w.Write([]byte(`{}`))
+		default:
+			for _, account := range accounts {
+				if strings.HasSuffix(r.URL.Path, "/accounts/"+account.hash) {
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"account": map[string]interface{}{
+							"account_id_hash": account.hash,
+							"service":         map[string]interface{}{"requires_user_operation": account.requiresUserOperation},
+						},
+					})
+					return
+				}
+			}
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestAggregateAndWait(t *testing.T) {
+	before := fakeAccountState{aggregatedAt: "2024-01-15T09:00:00+09:00", succeededAt: "2024-01-15T09:00:00+09:00"}
+	succeeded := fakeAccountState{aggregatedAt: "2024-01-15T10:00:00+09:00", succeededAt: "2024-01-15T10:00:00+09:00"}
+	withError := fakeAccountState{aggregatedAt: "2024-01-15T10:00:00+09:00", succeededAt: before.succeededAt, errorID: 4}
+	notSucceeded := fakeAccountState{aggregatedAt: "2024-01-15T10:00:00+09:00", succeededAt: before.succeededAt}
+	errorWithoutAggregation := fakeAccountState{aggregatedAt: before.aggregatedAt, succeededAt: before.succeededAt, errorID: 4}
+
+	tests := []struct {
+		name        string
+		account     *fakeAccount
+		hash        string
+		want        AggregationOutcome
+		wantErr     error
+		wantErrText string
+	}{
+		{"succeeded", &fakeAccount{hash: "a", before: before, after: &succeeded}, "a", AggregationSucceeded, nil, ""},
+		{"error id", &fakeAccount{hash: "a", before: before, after: &withError}, "a", AggregationFailed, nil, ""},
+		{"aggregated without success", &fakeAccount{hash: "a", before: before, after: &notSucceeded}, "a", AggregationFailed, nil, ""},
+		{"new error before aggregation time advanced", &fakeAccount{hash: "a", before: before, after: &errorWithoutAggregation}, "a", AggregationFailed, nil, ""},
+		{"timed out", &fakeAccount{hash: "a", before: before}, "a", AggregationTimedOut, nil, ""},
+		{"not found", &fakeAccount{hash: "a", before: before}, "b", "", ErrAccountNotFound, ""},
+		{"trigger fails", &fakeAccount{hash: "a", before: before, triggerStatus: http.StatusConflict}, "a", "", nil, "failed to trigger aggregation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, aggregationServer(t, tt.account))
+
+			got, err := c.AggregateAndWait(context.Background(), tt.hash, AggregateOptions{
+				PollInterval: time.Millisecond,
+				Timeout:      50 * time.Millisecond,
+			})
+			if tt.wantErr != nil || tt.wantErrText != "" {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("err = %v, want %v %q", err, tt.wantErr, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Outcome != tt.want {
+				t.Errorf("Outcome = %q, want %q", got.Outcome, tt.want)
+			}
+			if got.Name != "Account a" || got.PreviousAggregatedAt.IsZero() {
+				t.Errorf("result = %+v, want name and previous aggregation time of the account", got)
+			}
+			if tt.account.after != nil && got.ErrorID != AggregationErrorID(strconv.Itoa(tt.account.after.errorID)) {
+				t.Errorf("ErrorID = %v, want %d", got.ErrorID, tt.account.after.errorID)
+			}
+		})
+	}
+}
+
+func TestAggregateAndWaitContextCanceled(t *testing.T) {
+	c := newTestClient(t, aggregationServer(t, &fakeAccount{hash: "a"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.AggregateAndWait(ctx, "a", AggregateOptions{PollInterval: time.Millisecond, Timeout: time.Minute})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,130 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AssetHistoryGranularity is the interval between two points of the asset history
+type AssetHistoryGranularity string
+
+const (
+	AssetHistoryDaily   AssetHistoryGranularity = "day"
+	AssetHistoryWeekly  AssetHistoryGranularity = "week"
+	AssetHistoryMonthly AssetHistoryGranularity = "month"
+)
+
+// GetAssetHistory gets the asset amounts per asset class between from and to (inclusive)
+func (c *Client) GetAssetHistory(ctx context.Context, from, to time.Time, granularity AssetHistoryGranularity, opts ...RequestOption) (*AssetHistory, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("from (%s) must not be after to (%s)", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+
+	req, err := c.newRequest(ctx, "GET", "/sp2/asset_histories", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"from": from.In(tokyo).Format(time.DateOnly),
+		"to":   to.In(tokyo).Format(time.DateOnly),
+	}
+	if granularity != "" {
+		params["granularity"] = string(granularity)
+	}
+	c.addQueryParams(req, params)
+
+	var resp AssetHistoryResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return NewAssetHistory(&resp), nil
+}
+
+// AssetHistoryPoint is the amount of an asset class at a date
+type AssetHistoryPoint struct {
+	Date      time.Time
+	ClassID   int
+	ClassName string
+	Category  string
+	Amount    Money
+}
+
+// AssetHistory is a time series of asset amounts per asset class, ordered by date and class
+type AssetHistory struct {
+	Points []AssetHistoryPoint
+}
+
+// NewAssetHistory converts an asset history response into a time series
+func NewAssetHistory(resp *AssetHistoryResponse) *AssetHistory {
+	h := &AssetHistory{
+		Points: make([]AssetHistoryPoint, 0, len(resp.Histories)),
+	}
+
+	for _, entry := range resp.Histories {
+		h.Points = append(h.Points, AssetHistoryPoint{
+			Date:      entry.Date.Time,
+			ClassID:   entry.ClassID,
+			ClassName: entry.ClassName,
+			Category:  entry.Category,
+			Amount:    entry.Amount,
+		})
+	}
+
+	sort.SliceStable(h.Points, func(i, j int) bool {
+		if !h.Points[i].Date.Equal(h.Points[j].Date) {
+			return h.Points[i].Date.Before(h.Points[j].Date)
+		}
+		return h.Points[i].ClassID < h.Points[j].ClassID
+	})
+
+	return h
+}
+
+// Dates returns the distinct dates of the series in ascending order
+func (h *AssetHistory) Dates() []time.Time {
+	var dates []time.Time
+	for _, p := range h.Points {
+		if len(dates) == 0 || !dates[len(dates)-1].Equal(p.Date) {
+			dates = append(dates, p.Date)
+		}
+	}
+	return dates
+}
+
+// Class returns the points of a single asset class in ascending order of date
+func (h *AssetHistory) Class(classID int) []AssetHistoryPoint {
+	var points []AssetHistoryPoint
+	for _, p := range h.Points {
+		if p.ClassID == classID {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// Amount returns the amount of an asset class at a date
+func (h *AssetHistory) Amount(date time.Time, classID int) Money {
+	// history amounts are always in yen
+	amount := JPY(0)
+	for _, p := range h.Points {
+		if p.ClassID == classID && p.Date.Equal(date) {
+			amount.Amount += p.Amount.Amount
+		}
+	}
+	return amount
+}
+
+// Total returns the sum of all asset classes at a date
+func (h *AssetHistory) Total(date time.Time) Money {
+	total := JPY(0)
+	for _, p := range h.Points {
+		if p.Date.Equal(date) {
+			total.Amount += p.Amount.Amount
+		}
+	}
+	return total
+}
@@ -0,0 +1,159 @@
+package moneyforward
+
+import (
+	"context"
+	"sort"
+)
+
+// GetAssetClasses gets the master list of asset classes
+func (c *Client) GetAssetClasses(ctx context.Context, opts ...RequestOption) (*AssetClassesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/asset_classes", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AssetClassesResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetAssetSubclasses gets the master list of asset subclasses
+func (c *Client) GetAssetSubclasses(ctx context.Context, opts ...RequestOption) (*AssetSubclassesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/asset_subclasses", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AssetSubclassesResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AssetRegistry gets the asset class registry.
+// The registry is fetched once and cached on the client, use RefreshAssetRegistry to fetch it again.
+func (c *Client) AssetRegistry(ctx context.Context, opts ...RequestOption) (*AssetRegistry, error) {
+	c.assetsMu.Lock()
+	defer c.assetsMu.Unlock()
+
+	if c.assets != nil {
+		return c.assets, nil
+	}
+
+	return c.refreshAssetRegistryLocked(ctx, opts...)
+}
+
+// RefreshAssetRegistry fetches the asset class registry again and replaces the cached one
+func (c *Client) RefreshAssetRegistry(ctx context.Context, opts ...RequestOption) (*AssetRegistry, error) {
+	c.assetsMu.Lock()
+	defer c.assetsMu.Unlock()
+
+	return c.refreshAssetRegistryLocked(ctx, opts...)
+}
+
+func (c *Client) refreshAssetRegistryLocked(ctx context.Context, opts ...RequestOption) (*AssetRegistry, error) {
+	classes, err := c.GetAssetClasses(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	subclasses, err := c.GetAssetSubclasses(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.assets = NewAssetRegistry(classes, subclasses)
+	return c.assets, nil
+}
+
+// AssetClass is an asset class such as deposits or stocks
+type AssetClass struct {
+	ID         int
+	Name       string
+	Subclasses []*AssetSubclass
+}
+
+// AssetSubclass is a subclass of an asset class
+type AssetSubclass struct {
+	ID       int
+	Name     string
+	Unit     string
+	Ordering int
+	// Liquid reports whether the asset can readily be converted to cash
+	Liquid bool
+	Class  *AssetClass
+}
+
+// AssetRegistry resolves asset class and subclass IDs
+type AssetRegistry struct {
+	classes    map[int]*AssetClass
+	subclasses map[int]*AssetSubclass
+}
+
+// NewAssetRegistry builds a registry from the asset class and subclass responses.
+// Either response may be nil.
+func NewAssetRegistry(classes *AssetClassesResponse, subclasses *AssetSubclassesResponse) *AssetRegistry {
+	r := &AssetRegistry{
+		classes:    map[int]*AssetClass{},
+		subclasses: map[int]*AssetSubclass{},
+	}
+
+	if classes != nil {
+		for _, ac := range classes.AssetClasses {
+			r.classes[ac.ID] = &AssetClass{ID: ac.ID, Name: ac.Name}
+		}
+	}
+
+	if subclasses != nil {
+		for _, as := range subclasses.AssetSubclasses {
+			subclass := &AssetSubclass{
+				ID:       as.ID,
+				Name:     as.Name,
+				Unit:     as.Unit,
+				Ordering: as.Ordering,
+				Liquid:   as.Liquid == 1,
+			}
+			if class, ok := r.classes[as.ClassID]; ok {
+				subclass.Class = class
+				class.Subclasses = append(class.Subclasses, subclass)
+			}
+			r.subclasses[as.ID] = subclass
+		}
+	}
+
+	for _, class := range r.classes {
+		sort.SliceStable(class.Subclasses, func(i, j int) bool {
+			return class.Subclasses[i].Ordering < class.Subclasses[j].Ordering
+		})
+	}
+
+	return r
+}
+
+// Class returns the asset class with the given ID
+func (r *AssetRegistry) Class(id int) (*AssetClass, bool) {
+	class, ok := r.classes[id]
+	return class, ok
+}
+
+// Subclass returns the asset subclass with the given ID
+func (r *AssetRegistry) Subclass(id int) (*AssetSubclass, bool) {
+	subclass, ok := r.subclasses[id]
+	return subclass, ok
+}
+
+// Classes returns all asset classes ordered by ID
+func (r *AssetRegistry) Classes() []*AssetClass {
+	classes := make([]*AssetClass, 0, len(r.classes))
+	for _, class := range r.classes {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].ID < classes[j].ID
+	})
+	return classes
+}
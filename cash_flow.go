@@ -0,0 +1,80 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// cashFlowDateLayout is the date format expected by the cash flow term data endpoints
+const cashFlowDateLayout = "2006/01/02"
+
+// GetAccountCashFlow gets all transactions of an account between from and to (inclusive, by date in Asia/Tokyo).
+// The range is fetched in monthly windows, de-duplicated by ID and sorted by RecognizedAt.
+func (c *Client) GetAccountCashFlow(ctx context.Context, accountIDHash string, from, to time.Time, opts ...RequestOption) ([]*UserAssetAct, error) {
+	return fetchCashFlow(from, to, func(from, to string) (*CashFlowTermDataResponse, error) {
+		return c.GetAccountCashFlowTermData(ctx, accountIDHash, from, to, opts...)
+	})
+}
+
+// GetSubAccountCashFlow gets all transactions of a sub-account between from and to (inclusive, by date in Asia/Tokyo).
+// The range is fetched in monthly windows, de-duplicated by ID and sorted by RecognizedAt.
+func (c *Client) GetSubAccountCashFlow(ctx context.Context, subAccountIDHash string, from, to time.Time, opts ...RequestOption) ([]*UserAssetAct, error) {
+	return fetchCashFlow(from, to, func(from, to string) (*CashFlowTermDataResponse, error) {
+		return c.GetSubAccountCashFlowTermData(ctx, subAccountIDHash, from, to, opts...)
+	})
+}
+
+func fetchCashFlow(from, to time.Time, fetch func(from, to string) (*CashFlowTermDataResponse, error)) ([]*UserAssetAct, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, fmt.Errorf("from and to must be set")
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("from (%s) must not be after to (%s)", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+
+	seen := map[StringID]bool{}
+	var acts []*UserAssetAct
+	for _, window := range monthWindows(from, to) {
+		resp, err := fetch(window[0].Format(cashFlowDateLayout), window[1].Format(cashFlowDateLayout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch cash flow from %s to %s: %w",
+				window[0].Format(time.DateOnly), window[1].Format(time.DateOnly), err)
+		}
+
+		for i := range resp.UserAssetActs {
+			act := &resp.UserAssetActs[i].UserAssetAct
+			if seen[act.ID] {
+				continue
+			}
+			seen[act.ID] = true
+			acts = append(acts, act)
+		}
+	}
+
+	sort.SliceStable(acts, func(i, j int) bool {
+		return acts[i].RecognizedAt.Before(acts[j].RecognizedAt.Time)
+	})
+
+	return acts, nil
+}
+
+// monthWindows splits the dates from..to into windows that don't cross a calendar month.
+// Each window holds its first and last date, both inclusive.
+func monthWindows(from, to time.Time) [][2]time.Time {
+	start := truncateToDay(from)
+	end := truncateToDay(to)
+
+	var windows [][2]time.Time
+	for !start.After(end) {
+		monthEnd := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, tokyo)
+		if monthEnd.After(end) {
+			monthEnd = end
+		}
+		windows = append(windows, [2]time.Time{start, monthEnd})
+		start = monthEnd.AddDate(0, 0, 1)
+	}
+
+	return windows
+}
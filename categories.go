@@ -0,0 +1,233 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetCategories gets the master list of large and middle transaction categories.
+// Category names are localized according to the Accept-Language of the request.
+func (c *Client) GetCategories(ctx context.Context, opts ...RequestOption) (*CategoriesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/categories", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoriesResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CategoryTree gets the category tree with both Japanese and English names.
+// The tree is fetched once and cached on the client, use RefreshCategoryTree to fetch it again.
+func (c *Client) CategoryTree(ctx context.Context, opts ...RequestOption) (*CategoryTree, error) {
+	c.categoriesMu.Lock()
+	defer c.categoriesMu.Unlock()
+
+	if c.categories != nil {
+		return c.categories, nil
+	}
+
+	return c.refreshCategoryTreeLocked(ctx, opts...)
+}
+
+// RefreshCategoryTree fetches the category tree again and replaces the cached one
+func (c *Client) RefreshCategoryTree(ctx context.Context, opts ...RequestOption) (*CategoryTree, error) {
+	c.categoriesMu.Lock()
+	defer c.categoriesMu.Unlock()
+
+	return c.refreshCategoryTreeLocked(ctx, opts...)
+}
+
+func (c *Client) refreshCategoryTreeLocked(ctx context.Context, opts ...RequestOption) (*CategoryTree, error) {
+	ja, err := c.GetCategories(ctx, append(opts, WithHeader("Accept-Language", "ja-JP,ja;q=0.9"))...)
+	if err != nil {
+		return nil, err
+	}
+	en, err := c.GetCategories(ctx, append(opts, WithHeader("Accept-Language", "en-US,en;q=0.9"))...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.categories = NewCategoryTree(ja, en)
+	return c.categories, nil
+}
+
+// Category is a large or middle transaction category
+type Category struct {
+	ID     int
+	NameJa string
+	NameEn string
+	// Parent is the large category of a middle category, nil for large categories
+	Parent *Category
+	// Children are the middle categories of a large category
+	Children []*Category
+}
+
+// Name returns the category name in the given language ("ja" or "en"), falling back to the other one
+func (c *Category) Name(lang string) string {
+	if strings.HasPrefix(lang, "ja") {
+		if c.NameJa != "" {
+			return c.NameJa
+		}
+		return c.NameEn
+	}
+	if c.NameEn != "" {
+		return c.NameEn
+	}
+	return c.NameJa
+}
+
+// CategoryTree resolves category IDs to categories
+type CategoryTree struct {
+	Large  []*Category
+	large  map[int]*Category
+	middle map[int]*Category
+}
+
+// NewCategoryTree builds a tree from the Japanese and English category responses.
+// Either response may be nil.
+func NewCategoryTree(ja, en *CategoriesResponse) *CategoryTree {
+	t := &CategoryTree{
+		large:  map[int]*Category{},
+		middle: map[int]*Category{},
+	}
+
+	add := func(resp *CategoriesResponse, en bool) {
+		if resp == nil {
+			return
+		}
+		for _, lc := range resp.LargeCategories {
+			large, ok := t.large[lc.ID]
+			if !ok {
+				large = &Category{ID: lc.ID}
+				t.large[lc.ID] = large
+				t.Large = append(t.Large, large)
+			}
+			setName(large, lc.Name, en)
+
+			for _, mc := range lc.MiddleCategories {
+				middle, ok := t.middle[mc.ID]
+				if !ok {
+					middle = &Category{ID: mc.ID, Parent: large}
+					t.middle[mc.ID] = middle
+					large.Children = append(large.Children, middle)
+				}
+				setName(middle, mc.Name, en)
+			}
+		}
+	}
+	add(ja, false)
+	add(en, true)
+
+	return t
+}
+
+func setName(c *Category, name string, en bool) {
+	if en {
+		c.NameEn = name
+	} else {
+		c.NameJa = name
+	}
+}
+
+// LargeCategory returns the large category with the given ID
+func (t *CategoryTree) LargeCategory(id StringID) (*Category, bool) {
+	c, ok := t.large[atoiID(id)]
+	return c, ok
+}
+
+// MiddleCategory returns the middle category with the given ID
+func (t *CategoryTree) MiddleCategory(id StringID) (*Category, bool) {
+	c, ok := t.middle[atoiID(id)]
+	return c, ok
+}
+
+// Validate checks that largeID is a known large category and, if middleID is not 0,
+// that middleID is a middle category of it
+func (t *CategoryTree) Validate(largeID, middleID int) error {
+	large, ok := t.large[largeID]
+	if !ok {
+		return fmt.Errorf("unknown large category %d", largeID)
+	}
+	if middleID == 0 {
+		return nil
+	}
+
+	middle, ok := t.middle[middleID]
+	if !ok {
+		return fmt.Errorf("unknown middle category %d", middleID)
+	}
+	if middle.Parent != large {
+		return fmt.Errorf("middle category %d (%s) does not belong to large category %d (%s)",
+			middleID, middle.Name("en"), largeID, large.Name("en"))
+	}
+	return nil
+}
+
+// CategoryPath is the resolved large and middle category of a transaction.
+// Categories that could not be resolved are nil.
+type CategoryPath struct {
+	Large  *Category
+	Middle *Category
+}
+
+// Format returns the path in the given language, eg "Food > Dining out"
+func (p CategoryPath) Format(lang string) string {
+	var parts []string
+	if p.Large != nil {
+		parts = append(parts, p.Large.Name(lang))
+	}
+	if p.Middle != nil {
+		parts = append(parts, p.Middle.Name(lang))
+	}
+	return strings.Join(parts, " > ")
+}
+
+// String returns the path in English
+func (p CategoryPath) String() string {
+	return p.Format("en")
+}
+
+// Resolve returns the category path of a transaction
+func (t *CategoryTree) Resolve(act *UserAssetAct) CategoryPath {
+	var path CategoryPath
+	path.Large, _ = t.LargeCategory(act.LargeCategoryID)
+	path.Middle, _ = t.MiddleCategory(act.MiddleCategoryID)
+	if path.Large == nil && path.Middle != nil {
+		path.Large = path.Middle.Parent
+	}
+	return path
+}
+
+// CategorizedUserAssetAct is a transaction annotated with its category path
+type CategorizedUserAssetAct struct {
+	*UserAssetAct
+	Category CategoryPath
+}
+
+// Annotate resolves the category path of each transaction
+func (t *CategoryTree) Annotate(acts []*UserAssetAct) []CategorizedUserAssetAct {
+	annotated := make([]CategorizedUserAssetAct, len(acts))
+	for i, act := range acts {
+		annotated[i] = CategorizedUserAssetAct{
+			UserAssetAct: act,
+			Category:     t.Resolve(act),
+		}
+	}
+	return annotated
+}
+
+// atoiID converts a numeric StringID to an int, returning -1 if it isn't numeric
+func atoiID(id StringID) int {
+	i, err := strconv.Atoi(string(id))
+	if err != nil {
+		return -1
+	}
+	return i
+}
@@ -2,15 +2,21 @@ package moneyforward
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 const (
-	defaultBaseURL = "https://moneyforward.com"
+	defaultBaseURL        = "https://moneyforward.com"
+	defaultUserAgent      = "iPhone(iOS:18.2), MoneyFwd-SP(18.1.0) Build:10614"
+	defaultAcceptLanguage = "en-US,en;q=0.9"
 )
 
 // Client represents a MoneyForward API client
@@ -22,17 +28,115 @@ type Client struct {
 	// Optional headers
 	userAgent      string
 	acceptLanguage string
+
+	// log receives diagnostic output, nothing is logged when nil
+	log *slog.Logger
+
+	// retryPolicy is applied to idempotent requests, no retries are made when nil
+	retryPolicy *RetryPolicy
+
+	// limiter and concurrency throttle requests, both are disabled when nil
+	limiter     *rateLimiter
+	concurrency semaphore
+
+	// categories caches the category tree, see CategoryTree
+	categoriesMu sync.Mutex
+	categories   *CategoryTree
+
+	// assets caches the asset class registry, see AssetRegistry
+	assetsMu sync.Mutex
+	assets   *AssetRegistry
+
+	// baseURLErr holds an invalid URL passed to WithBaseURL and is returned by every request
+	// until a valid URL is set with SetBaseURL
+	baseURLErr error
+}
+
+// ClientOption configures a Client in NewClient
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	client  *Client
+	timeout time.Duration
+}
+
+// WithHTTPClient sets the http.Client used for requests, eg to inject a proxy or custom transport.
+// A nil client keeps http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		if httpClient != nil {
+			cfg.client.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL overrides the default API URL
+func WithBaseURL(urlStr string) ClientOption {
+	return func(cfg *clientConfig) {
+		if err := cfg.client.SetBaseURL(urlStr); err != nil {
+			cfg.client.baseURLErr = fmt.Errorf("invalid base URL: %w", err)
+		}
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.userAgent = userAgent
+	}
+}
+
+// WithAcceptLanguage overrides the Accept-Language header sent with every request.
+// Note that the language changes localized strings in responses, such as AccountType.
+func WithAcceptLanguage(acceptLanguage string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.acceptLanguage = acceptLanguage
+	}
+}
+
+// WithTimeout sets the timeout of the underlying http.Client
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used for diagnostic output
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.log = logger
+	}
 }
 
 // NewClient creates a new MoneyForward API client
-func NewClient(cookieString string) *Client {
+func NewClient(cookieString string, opts ...ClientOption) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: http.DefaultClient,
-		cookie:     cookieString,
+	cfg := &clientConfig{
+		client: &Client{
+			baseURL:        baseURL,
+			httpClient:     http.DefaultClient,
+			cookie:         cookieString,
+			userAgent:      defaultUserAgent,
+			acceptLanguage: defaultAcceptLanguage,
+			limiter:        newRateLimiter(defaultRequestsPerSecond, defaultBurst),
+			concurrency:    newSemaphore(defaultMaxConcurrency),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	c := cfg.client
+	if cfg.timeout > 0 {
+		// copy so that a shared client (eg http.DefaultClient) isn't modified
+		httpClient := *c.httpClient
+		httpClient.Timeout = cfg.timeout
+		c.httpClient = &httpClient
 	}
+
+	return c
 }
 
 // SetCookie sets the authentication cookie for requests
@@ -40,17 +144,98 @@ func (c *Client) SetCookie(cookie string) {
 	c.cookie = cookie
 }
 
-// RequestOption allows customizing requests
-type RequestOption func(*http.Request)
+// SetLogger sets the logger used for diagnostic output
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.log = logger
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.log == nil {
+		return discardLogger
+	}
+	return c.log
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that drops all records
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// RequestOption allows customizing a single request
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	headers    http.Header
+	query      url.Values
+	timeout    time.Duration
+	idempotent *bool
+	editors    []func(*http.Request)
+}
+
+type requestOptionsKey struct{}
 
 // WithHeader adds a custom header to the request
 func WithHeader(key, value string) RequestOption {
-	return func(req *http.Request) {
-		req.Header.Set(key, value)
+	return func(o *requestOptions) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithQueryParam sets an extra query parameter on the request, overriding any value set by the method
+func WithQueryParam(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.query.Set(key, value)
+	}
+}
+
+// WithRequestTimeout limits the duration of the request, including reading the response body
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
 	}
 }
 
-func (c *Client) newRequest(method, spath string, opts ...RequestOption) (*http.Request, error) {
+// WithIdempotent marks whether the request is safe to send more than once.
+// By default only GET requests are considered idempotent.
+func WithIdempotent(idempotent bool) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = &idempotent
+	}
+}
+
+// WithRequestEditor registers a function that can modify the request right before it is sent
+func WithRequestEditor(fn func(*http.Request)) RequestOption {
+	return func(o *requestOptions) {
+		o.editors = append(o.editors, fn)
+	}
+}
+
+// isIdempotent reports whether req may be repeated safely
+func (o *requestOptions) isIdempotent(req *http.Request) bool {
+	if o.idempotent != nil {
+		return *o.idempotent
+	}
+	return req.Method == http.MethodGet
+}
+
+// requestOptionsFrom returns the options attached to a request by newRequest
+func requestOptionsFrom(req *http.Request) *requestOptions {
+	if o, ok := req.Context().Value(requestOptionsKey{}).(*requestOptions); ok {
+		return o
+	}
+	return &requestOptions{}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, spath string, opts ...RequestOption) (*http.Request, error) {
+	if c.baseURLErr != nil {
+		return nil, c.baseURLErr
+	}
+
 	u := *c.baseURL
 	// Use double slash for sp2 endpoints
 	if spath[0] == '/' {
@@ -58,7 +243,18 @@ func (c *Client) newRequest(method, spath string, opts ...RequestOption) (*http.
 	}
 	u.Path = spath
 
-	req, err := http.NewRequest(method, u.String(), nil)
+	o := &requestOptions{
+		headers: http.Header{},
+		query:   url.Values{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// options are carried on the context so that do can apply them after the method has added its own params
+	ctx = context.WithValue(ctx, requestOptionsKey{}, o)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -68,55 +264,199 @@ func (c *Client) newRequest(method, spath string, opts ...RequestOption) (*http.
 		req.Header.Set("Cookie", c.cookie)
 	}
 
-	req.Header.Set("User-Agent", "iPhone(iOS:18.2), MoneyFwd-SP(18.1.0) Build:10614")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept-Language", c.acceptLanguage)
 	req.Header.Set("Accept", "*/*")
 
-	for _, opt := range opts {
-		opt(req)
+	for key, values := range o.headers {
+		req.Header[key] = values
 	}
 
 	return req, nil
 }
 
-func (c *Client) do(req *http.Request, v interface{}) error {
-	resp, err := c.httpClient.Do(req)
+// newJSONRequest creates a request with body encoded as JSON
+func (c *Client) newJSONRequest(ctx context.Context, method, spath string, body interface{}, opts ...RequestOption) (*http.Request, error) {
+	req, err := c.newRequest(ctx, method, spath, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	// make a new reader with body to allow reading it again
-	bodyCloser := io.NopCloser(bytes.NewBuffer(body))
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, v interface{}) error {
+	o := requestOptionsFrom(req)
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	if len(o.query) > 0 {
+		q := req.URL.Query()
+		for key, values := range o.query {
+			q[key] = values
+		}
+		req.URL.RawQuery = q.Encode()
 	}
 
-	if v != nil {
-		if err := json.NewDecoder(io.NopCloser(bodyCloser)).Decode(v); err != nil {
-			// output the raw resp.Body on error
-			fmt.Printf("Failed to decode response: %s\n", string(body))
+	if o.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), o.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	for _, edit := range o.editors {
+		edit(req)
+	}
+
+	maxAttempts := 1
+	if c.retryPolicy != nil && o.isIdempotent(req) {
+		maxAttempts = max(c.retryPolicy.MaxAttempts, 1)
+	}
+
+	var body []byte
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		var err error
+		body, retryAfter, err = c.send(req)
+		if err == nil {
+			break
+		}
 
+		if attempt >= maxAttempts || !c.retryPolicy.shouldRetry(req.Context(), err) {
 			return err
 		}
+
+		delay, ok := c.retryPolicy.delay(attempt, retryAfter)
+		if !ok {
+			c.logger().Debug("not retrying request, Retry-After exceeds the maximum delay",
+				"method", req.Method,
+				"path", req.URL.Path,
+				"retry_after", retryAfter,
+			)
+			return err
+		}
+		c.logger().Debug("retrying request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err,
+		)
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(RetryEvent{
+				Method:  req.Method,
+				Path:    req.URL.Path,
+				Attempt: attempt,
+				Delay:   delay,
+				Err:     err,
+			})
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return err
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return err
+		}
+	}
+
+	// responses such as 204 No Content have nothing to decode
+	if v != nil && len(body) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		if err := dec.Decode(v); err != nil {
+			decodeErr := newDecodeError(req, v, body, c.cookie, err, dec.InputOffset())
+			c.logger().Debug("failed to decode response",
+				"path", decodeErr.Path,
+				"type", decodeErr.Type,
+				"offset", decodeErr.Offset,
+				"field", decodeErr.Field,
+				"body", decodeErr.Body,
+			)
+
+			return decodeErr
+		}
 	}
 
 	return nil
 }
 
-// GetHomeTimeline gets the home timeline data
-func (c *Client) GetHomeTimeline(limit int) (*HomeTimelineResponse, error) {
-	req, err := c.newRequest("GET", "/sp2/home_timeline")
+// send performs a single attempt of req and returns the response body.
+// For non-2xx responses it returns an *APIError along with the server's Retry-After.
+func (c *Client) send(req *http.Request) ([]byte, time.Duration, error) {
+	if err := c.concurrency.acquire(req.Context()); err != nil {
+		return nil, 0, err
+	}
+	defer c.concurrency.release()
+
+	if err := c.limiter.wait(req.Context()); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return body, parseRetryAfter(resp.Header), newAPIError(req, resp, body, c.cookie)
+	}
+
+	return body, 0, nil
+}
+
+// rewindRequest prepares req to be sent again by resetting its body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// GetHomeTimeline gets the latest page of the home timeline
+func (c *Client) GetHomeTimeline(ctx context.Context, limit int, opts ...RequestOption) (*HomeTimelineResponse, error) {
+	return c.GetHomeTimelinePage(ctx, limit, "", opts...)
+}
+
+// GetHomeTimelinePage gets the page of the home timeline before the until cursor, as returned in
+// Self.Until of the previous page. An empty cursor gets the latest page.
+func (c *Client) GetHomeTimelinePage(ctx context.Context, limit int, until string, opts ...RequestOption) (*HomeTimelineResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/home_timeline", opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Add limit parameter
+	// Add limit and cursor parameters
 	params := map[string]string{
 		"limit": fmt.Sprintf("%d", limit),
 	}
+	if until != "" {
+		params["until"] = until
+	}
 	c.addQueryParams(req, params)
 
 	var resp HomeTimelineResponse
@@ -128,8 +468,8 @@ func (c *Client) GetHomeTimeline(limit int) (*HomeTimelineResponse, error) {
 }
 
 // ForceUpdate forces an update of the data
-func (c *Client) ForceUpdate() error {
-	req, err := c.newRequest("GET", "/sp2/force_update")
+func (c *Client) ForceUpdate(ctx context.Context, opts ...RequestOption) error {
+	req, err := c.newRequest(ctx, "GET", "/sp2/force_update", opts...)
 	if err != nil {
 		return err
 	}
@@ -138,8 +478,8 @@ func (c *Client) ForceUpdate() error {
 }
 
 // GetAccountSummaries gets account summaries
-func (c *Client) GetAccountSummaries() (*AccountSummariesResponse, error) {
-	req, err := c.newRequest("GET", "/sp2/account_summaries")
+func (c *Client) GetAccountSummaries(ctx context.Context, opts ...RequestOption) (*AccountSummariesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/account_summaries", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -153,8 +493,8 @@ func (c *Client) GetAccountSummaries() (*AccountSummariesResponse, error) {
 }
 
 // GetTransactions gets transaction data
-func (c *Client) GetTransactions() (*TransactionsResponse, error) {
-	req, err := c.newRequest("GET", "/sp2/transactions")
+func (c *Client) GetTransactions(ctx context.Context, opts ...RequestOption) (*TransactionsResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/transactions", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +508,8 @@ func (c *Client) GetTransactions() (*TransactionsResponse, error) {
 }
 
 // GetUserAssetActivities gets user asset activities with pagination and filters
-func (c *Client) GetUserAssetActivities(params UserAssetActsParams) (*UserAssetActsResponse, error) {
-	req, err := c.newRequest("GET", "/sp2/user_asset_acts")
+func (c *Client) GetUserAssetActivities(ctx context.Context, params UserAssetActsParams, opts ...RequestOption) (*UserAssetActsResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/user_asset_acts", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -219,8 +559,8 @@ type UserAssetActsParams struct {
 }
 
 // GetUserAssetActivity gets a specific user asset activity by ID
-func (c *Client) GetUserAssetActivity(activityID string) (*UserAssetActResponse, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/sp2/user_asset_acts/%s", activityID))
+func (c *Client) GetUserAssetActivity(ctx context.Context, activityID string, opts ...RequestOption) (*UserAssetActResponse, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/sp2/user_asset_acts/%s", activityID), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -234,8 +574,8 @@ func (c *Client) GetUserAssetActivity(activityID string) (*UserAssetActResponse,
 }
 
 // GetAccount gets details for a specific account
-func (c *Client) GetAccount(mfPath MFShowPath) (*AccountResponse, error) {
-	req, err := c.newRequest("GET", string(mfPath))
+func (c *Client) GetAccount(ctx context.Context, mfPath MFShowPath, opts ...RequestOption) (*AccountResponse, error) {
+	req, err := c.newRequest(ctx, "GET", string(mfPath), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +602,7 @@ func (c *Client) SetBaseURL(urlStr string) error {
 		return err
 	}
 	c.baseURL = baseURL
+	c.baseURLErr = nil
 	return nil
 }
 
@@ -275,8 +616,8 @@ func (c *Client) addQueryParams(req *http.Request, params map[string]string) {
 }
 
 // GetAccountCashFlowTermData gets cash flow data for a specific sub-account within a date range
-func (c *Client) GetAccountCashFlowTermData(accountIDHash string, from, to string) (*CashFlowTermDataResponse, error) {
-	req, err := c.newRequest("GET", "/sp/cf_term_data_by_account")
+func (c *Client) GetAccountCashFlowTermData(ctx context.Context, accountIDHash string, from, to string, opts ...RequestOption) (*CashFlowTermDataResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp/cf_term_data_by_account", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -298,8 +639,8 @@ func (c *Client) GetAccountCashFlowTermData(accountIDHash string, from, to strin
 }
 
 // GetSubAccountCashFlowTermData gets cash flow data for a specific sub-account within a date range
-func (c *Client) GetSubAccountCashFlowTermData(subAccountIDHash string, from, to string) (*CashFlowTermDataResponse, error) {
-	req, err := c.newRequest("GET", "/sp/cf_term_data_by_sub_account")
+func (c *Client) GetSubAccountCashFlowTermData(ctx context.Context, subAccountIDHash string, from, to string, opts ...RequestOption) (*CashFlowTermDataResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp/cf_term_data_by_sub_account", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -321,8 +662,8 @@ func (c *Client) GetSubAccountCashFlowTermData(subAccountIDHash string, from, to
 }
 
 // GetSubAccountDetail gets detailed information for a specific sub-account
-func (c *Client) GetAccountDetail(accountIDHash string) (*AccountDetailResponse, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/sp/service_detail/%s", accountIDHash))
+func (c *Client) GetAccountDetail(ctx context.Context, accountIDHash string, opts ...RequestOption) (*AccountDetailResponse, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/sp/service_detail/%s", accountIDHash), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -341,8 +682,8 @@ func (c *Client) GetAccountDetail(accountIDHash string) (*AccountDetailResponse,
 }
 
 // GetSubAccountDetail gets detailed information for a specific sub-account
-func (c *Client) GetSubAccountDetail(accountIDHash, subAccountIDHash string) (*AccountDetailResponse, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/sp/service_detail/%s", accountIDHash))
+func (c *Client) GetSubAccountDetail(ctx context.Context, accountIDHash, subAccountIDHash string, opts ...RequestOption) (*AccountDetailResponse, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/sp/service_detail/%s", accountIDHash), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -362,9 +703,9 @@ func (c *Client) GetSubAccountDetail(accountIDHash, subAccountIDHash string) (*A
 }
 
 // TriggerAccountAggregation triggers a data aggregation for a specific account
-func (c *Client) TriggerAccountAggregation(accountIDHash string) error {
+func (c *Client) TriggerAccountAggregation(ctx context.Context, accountIDHash string, opts ...RequestOption) error {
 	path := fmt.Sprintf("/sp2/accounts/%s/aggregation_queue", accountIDHash)
-	req, err := c.newRequest("POST", path)
+	req, err := c.newRequest(ctx, "POST", path, opts...)
 	if err != nil {
 		return err
 	}
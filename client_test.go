@@ -0,0 +1,76 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client talking to handler without rate or concurrency limits
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]ClientOption{WithBaseURL(srv.URL), WithRateLimit(0, 0), WithMaxConcurrency(0)}, opts...)
+	return NewClient("session=secret-cookie-value", opts...)
+}
+
+func TestClientOptions(t *testing.T) {
+	var got *http.Request
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		fmt.Fprint(w, `{}`)
+	}, WithUserAgent("test-agent"), WithAcceptLanguage("ja-JP"), WithHTTPClient(nil), WithTimeout(time.Minute))
+
+	if c.httpClient == http.DefaultClient || c.httpClient.Timeout != time.Minute || http.DefaultClient.Timeout != 0 {
+		t.Errorf("WithTimeout: httpClient = %+v, want a copy of http.DefaultClient with a timeout", c.httpClient)
+	}
+
+	_, err := c.GetAccountSummaries(context.Background(),
+		WithHeader("X-Test", "1"),
+		WithQueryParam("extra", "a b"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"User-Agent", got.UserAgent(), "test-agent"},
+		{"Accept-Language", got.Header.Get("Accept-Language"), "ja-JP"},
+		{"Cookie", got.Header.Get("Cookie"), "session=secret-cookie-value"},
+		{"X-Test", got.Header.Get("X-Test"), "1"},
+		{"query", got.URL.Query().Get("extra"), "a b"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestClientInvalidBaseURL(t *testing.T) {
+	c := NewClient("", WithBaseURL("http://[::1"))
+	if _, err := c.GetAccountSummaries(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid base URL") {
+		t.Fatalf("err = %v, want an invalid base URL error", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	if err := c.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetAccountSummaries(context.Background()); err != nil {
+		t.Errorf("after SetBaseURL: err = %v, want nil", err)
+	}
+}
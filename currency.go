@@ -0,0 +1,265 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoRate is returned when no exchange rate is available for a conversion
+	ErrNoRate = errors.New("moneyforward: no exchange rate available")
+	// ErrInvalidRate is returned for exchange rates that aren't finite and positive
+	ErrInvalidRate = errors.New("moneyforward: invalid exchange rate")
+)
+
+// ExchangeRate is the rate used to convert From into To, 1 From = Rate To
+type ExchangeRate struct {
+	From string
+	To   string
+	Rate float64
+	// Source describes where the rate came from, eg "user_asset_act" for the rate embedded in an activity
+	Source string
+	// AsOf is the date the rate applies to, zero if unknown
+	AsOf time.Time
+}
+
+// RateSource provides exchange rates
+type RateSource interface {
+	// Rate returns the rate to convert from into to at the given time
+	Rate(ctx context.Context, from, to string, at time.Time) (ExchangeRate, error)
+}
+
+// Conversion records an amount converted into the reporting currency and the rate that was used
+type Conversion struct {
+	Original  Money
+	Converted Money
+	Rate      ExchangeRate
+}
+
+// Converter normalizes amounts into a reporting currency
+type Converter struct {
+	// Target is the reporting currency, defaults to JPY
+	Target string
+	// Source provides exchange rates. When nil, only conversions to JPY are possible,
+	// using the JPYRate embedded in each activity or holding.
+	Source RateSource
+}
+
+func (c *Converter) target() string {
+	if c.Target == "" {
+		return DefaultCurrency
+	}
+	return normalizeCurrency(c.Target)
+}
+
+// ConvertUserAssetAct converts the original amount of an activity into the reporting currency.
+// For JPY activities Amount is used, as the API doesn't always send an original amount for them.
+func (c *Converter) ConvertUserAssetAct(ctx context.Context, act *UserAssetAct) (*Conversion, error) {
+	original := act.OrigAmount
+	if currency := normalizeCurrency(act.Currency); currency == "" || currency == DefaultCurrency {
+		original = act.Amount
+	}
+	return c.convert(ctx, original, act.RecognizedAt.Time, act.JPYRate, "user_asset_act")
+}
+
+// ConvertUserAssetDet converts the value of a holding into the reporting currency
+func (c *Converter) ConvertUserAssetDet(ctx context.Context, det *UserAssetDet) (*Conversion, error) {
+	return c.convert(ctx, det.Value, det.UpdatedAt.Time, det.JPYRate, "user_asset_det")
+}
+
+// Convert converts m into the reporting currency using the rate at the given time
+func (c *Converter) Convert(ctx context.Context, m Money, at time.Time) (*Conversion, error) {
+	return c.convert(ctx, m, at, 0, "")
+}
+
+func (c *Converter) convert(ctx context.Context, m Money, at time.Time, jpyRate float64, embeddedSource string) (*Conversion, error) {
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	from, to := m.Currency, c.target()
+
+	var rate ExchangeRate
+	switch {
+	case from == to:
+		rate = ExchangeRate{From: from, To: to, Rate: 1, Source: "identity", AsOf: at}
+	case c.Source != nil:
+		var err error
+		rate, err = c.Source.Rate(ctx, from, to, at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s/%s rate: %w", from, to, err)
+		}
+	case to == "JPY" && jpyRate > 0:
+		rate = ExchangeRate{From: from, To: to, Rate: jpyRate, Source: embeddedSource, AsOf: at}
+	default:
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+
+	// rates come from a pluggable RateSource, so don't trust them
+	converted, err := m.Convert(rate.Rate, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s to %s with %s rate: %w", from, to, rate.Source, err)
+	}
+
+	return &Conversion{
+		Original:  m,
+		Converted: converted,
+		Rate:      rate,
+	}, nil
+}
+
+// Convert returns m converted into currency at rate (1 unit of m's currency = rate units of currency),
+// rounding half away from zero to the minor unit of currency.
+// It fails with ErrInvalidRate unless rate is finite and positive.
+func (m Money) Convert(rate float64, currency string) (Money, error) {
+	if !validRate(rate) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidRate, rate)
+	}
+	currency = normalizeCurrency(currency)
+
+	// minor * rate * 10^(toExp - fromExp), computed exactly
+	v := new(big.Rat).SetInt64(m.Amount)
+	v.Mul(v, new(big.Rat).SetFloat64(rate))
+	shift := CurrencyExponent(currency) - CurrencyExponent(m.currency())
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	amount, err := roundRat(v)
+	if err != nil {
+		return Money{}, fmt.Errorf("failed to convert %s: %w", m, err)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// validRate reports whether rate is finite and positive
+func validRate(rate float64) bool {
+	return !math.IsNaN(rate) && !math.IsInf(rate, 0) && rate > 0
+}
+
+// roundRat rounds r to the nearest integer, half away from zero
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(r.Num())
+	q, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("%w: amount %s out of range", ErrOverflow, q)
+	}
+	return q.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// EmbeddedRates is a RateSource built from the JPYRate embedded in activities and holdings.
+// Conversions between two foreign currencies are done through JPY.
+type EmbeddedRates struct {
+	mu    sync.RWMutex
+	rates map[string][]ExchangeRate
+}
+
+// NewEmbeddedRates returns an empty EmbeddedRates, use AddUserAssetActs and AddUserAssetDets to fill it
+func NewEmbeddedRates() *EmbeddedRates {
+	return &EmbeddedRates{
+		rates: map[string][]ExchangeRate{},
+	}
+}
+
+// AddUserAssetActs records the JPY rates of activities in a foreign currency
+func (e *EmbeddedRates) AddUserAssetActs(acts ...*UserAssetAct) {
+	for _, act := range acts {
+		e.add(act.Currency, act.JPYRate, act.RecognizedAt.Time, "user_asset_act")
+	}
+}
+
+// AddUserAssetDets records the JPY rates of holdings in a foreign currency
+func (e *EmbeddedRates) AddUserAssetDets(dets ...*UserAssetDet) {
+	for _, det := range dets {
+		e.add(det.Currency, det.JPYRate, det.UpdatedAt.Time, "user_asset_det")
+	}
+}
+
+func (e *EmbeddedRates) add(currency string, rate float64, at time.Time, source string) {
+	currency = normalizeCurrency(currency)
+	if currency == "" || currency == "JPY" || !validRate(rate) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rates[currency] = append(e.rates[currency], ExchangeRate{From: currency, To: "JPY", Rate: rate, Source: source, AsOf: at})
+}
+
+// Rate returns the recorded rate closest to at
+func (e *EmbeddedRates) Rate(ctx context.Context, from, to string, at time.Time) (ExchangeRate, error) {
+	from, to = normalizeCurrency(from), normalizeCurrency(to)
+
+	fromJPY, err := e.jpyRate(from, at)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	toJPY, err := e.jpyRate(to, at)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+
+	asOf := fromJPY.AsOf
+	if to != "JPY" && (from == "JPY" || toJPY.AsOf.Before(asOf)) {
+		asOf = toJPY.AsOf
+	}
+
+	return ExchangeRate{
+		From:   from,
+		To:     to,
+		Rate:   fromJPY.Rate / toJPY.Rate,
+		Source: "embedded",
+		AsOf:   asOf,
+	}, nil
+}
+
+// jpyRate returns the rate of currency into JPY closest to at
+func (e *EmbeddedRates) jpyRate(currency string, at time.Time) (ExchangeRate, error) {
+	if currency == "JPY" {
+		return ExchangeRate{From: "JPY", To: "JPY", Rate: 1}, nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rates := e.rates[currency]
+	if len(rates) == 0 {
+		return ExchangeRate{}, fmt.Errorf("%w: %s to JPY", ErrNoRate, currency)
+	}
+
+	closest := rates[0]
+	for _, rate := range rates[1:] {
+		if absDuration(rate.AsOf.Sub(at)) < absDuration(closest.AsOf.Sub(at)) {
+			closest = rate
+		}
+	}
+	return closest, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
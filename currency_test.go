@@ -0,0 +1,127 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		rate     float64
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{"usd to jpy", NewMoney(1050, "USD"), 150.25, "JPY", JPY(1578), nil},
+		{"jpy to usd", JPY(1000), 0.0067, "USD", NewMoney(670, "USD"), nil},
+		{"half away from zero", JPY(-1), 0.005, "USD", NewMoney(-1, "USD"), nil},
+		{"no currency", Money{Amount: 100}, 0.01, "usd", NewMoney(100, "USD"), nil},
+		{"three decimals", NewMoney(1000, "USD"), 0.3, "KWD", NewMoney(3000, "KWD"), nil},
+		{"nan", JPY(100), math.NaN(), "USD", Money{}, ErrInvalidRate},
+		{"inf", JPY(100), math.Inf(1), "USD", Money{}, ErrInvalidRate},
+		{"negative inf", JPY(100), math.Inf(-1), "USD", Money{}, ErrInvalidRate},
+		{"zero", JPY(100), 0, "USD", Money{}, ErrInvalidRate},
+		{"negative", JPY(100), -1, "USD", Money{}, ErrInvalidRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Convert(tt.rate, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyConvertOverflow(t *testing.T) {
+	if got, err := JPY(math.MaxInt64).Convert(1e10, "USD"); err == nil {
+		t.Errorf("Convert() = %v, want an error", got)
+	}
+}
+
+type fixedRate float64
+
+func (r fixedRate) Rate(ctx context.Context, from, to string, at time.Time) (ExchangeRate, error) {
+	return ExchangeRate{From: from, To: to, Rate: float64(r), Source: "fixed"}, nil
+}
+
+func TestConverterInvalidRate(t *testing.T) {
+	for _, rate := range []float64{math.Inf(1), math.NaN(), 0} {
+		c := &Converter{Target: "USD", Source: fixedRate(rate)}
+		if _, err := c.Convert(context.Background(), JPY(100), time.Now()); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("Convert() with rate %v error = %v, want ErrInvalidRate", rate, err)
+		}
+	}
+}
+
+func TestConverterEmbeddedRate(t *testing.T) {
+	act := &UserAssetAct{OrigAmount: NewMoney(-1050, "USD"), Currency: "USD", JPYRate: 150}
+
+	conversion, err := (&Converter{}).ConvertUserAssetAct(context.Background(), act)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conversion.Converted != JPY(-1575) {
+		t.Errorf("Converted = %#v, want %#v", conversion.Converted, JPY(-1575))
+	}
+
+	act.JPYRate = 0
+	if _, err := (&Converter{}).ConvertUserAssetAct(context.Background(), act); !errors.Is(err, ErrNoRate) {
+		t.Errorf("ConvertUserAssetAct() without rate error = %v, want ErrNoRate", err)
+	}
+}
+
+func TestConverterJPYUserAssetAct(t *testing.T) {
+	tests := []struct {
+		name string
+		act  *UserAssetAct
+		want Money
+	}{
+		{"without orig_amount", &UserAssetAct{Amount: JPY(-1500), Currency: "JPY"}, JPY(-1500)},
+		{"without currency", &UserAssetAct{Amount: JPY(-1500)}, JPY(-1500)},
+		{"lower case currency", &UserAssetAct{Amount: JPY(-1500), OrigAmount: JPY(-1500), Currency: "jpy"}, JPY(-1500)},
+	}
+
+	for _, tt := range tests {
+		conversion, err := (&Converter{}).ConvertUserAssetAct(context.Background(), tt.act)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if conversion.Converted != tt.want || conversion.Original != tt.want {
+			t.Errorf("%s: conversion = %+v, want %#v", tt.name, conversion, tt.want)
+		}
+	}
+}
+
+func TestEmbeddedRatesSkipsInvalidRates(t *testing.T) {
+	at := time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo)
+	rates := NewEmbeddedRates()
+	rates.AddUserAssetActs(
+		&UserAssetAct{Currency: "USD", JPYRate: math.NaN(), RecognizedAt: Timestamp{at}},
+		&UserAssetAct{Currency: "USD", JPYRate: math.Inf(1), RecognizedAt: Timestamp{at}},
+		&UserAssetAct{Currency: "EUR", JPYRate: math.Inf(-1), RecognizedAt: Timestamp{at}},
+		&UserAssetAct{Currency: "USD", JPYRate: 150, RecognizedAt: Timestamp{at.AddDate(0, 0, -10)}},
+	)
+
+	rate, err := rates.Rate(context.Background(), "USD", "JPY", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Rate != 150 {
+		t.Errorf("USD/JPY rate = %v, want 150 from the only valid activity", rate.Rate)
+	}
+
+	if _, err := rates.Rate(context.Background(), "EUR", "JPY", at); !errors.Is(err, ErrNoRate) {
+		t.Errorf("EUR/JPY error = %v, want ErrNoRate", err)
+	}
+}
@@ -0,0 +1,328 @@
+package moneyforward
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sentinel errors that an *APIError can be matched against with errors.Is
+var (
+	// ErrUnauthorized is returned when the session cookie is missing, expired or rejected
+	ErrUnauthorized = errors.New("moneyforward: unauthorized")
+	// ErrNotFound is returned when the requested resource (eg an unknown account_id_hash) does not exist
+	ErrNotFound = errors.New("moneyforward: not found")
+	// ErrRateLimited is returned when MoneyForward throttles the client
+	ErrRateLimited = errors.New("moneyforward: rate limited")
+	// ErrServer is returned for any 5xx response
+	ErrServer = errors.New("moneyforward: server error")
+)
+
+const redactedPlaceholder = "[REDACTED]"
+
+// APIError is returned when the API responds with a non-2xx status code
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	// Body is the raw response body with any cookie values redacted
+	Body string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request %s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte, cookie string) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Body:       redactCookie(string(body), cookie),
+	}
+}
+
+// redactCookie removes the cookie string and each of its values from s
+func redactCookie(s, cookie string) string {
+	if cookie == "" {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, cookie, redactedPlaceholder)
+	for _, part := range strings.Split(cookie, ";") {
+		_, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		// skip short values to avoid mangling unrelated content
+		if !ok || len(value) < 8 {
+			continue
+		}
+		s = strings.ReplaceAll(s, value, redactedPlaceholder)
+	}
+
+	return s
+}
+
+// maxDecodeErrorBody is the maximum number of bytes of the response body kept in a DecodeError
+const maxDecodeErrorBody = 512
+
+// DecodeError is returned when a response body could not be decoded into the target type
+type DecodeError struct {
+	// Type is the Go type the response was decoded into
+	Type string
+	Path string
+	// Offset is the byte offset in the body at which decoding failed, if known
+	Offset int64
+	// Field is the dotted path of the struct field that failed to decode, if known
+	Field string
+	// Body is the cookie-redacted response body, truncated to maxDecodeErrorBody bytes
+	Body string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("failed to decode response of %s into %s at offset %d", e.Path, e.Type, e.Offset)
+	if e.Field != "" {
+		msg += fmt.Sprintf(" (field %s)", e.Field)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError describes err from decoding body into v. inputOffset is the decoder's offset, used
+// when the error doesn't carry one, eg for errors returned by UnmarshalJSON methods.
+func newDecodeError(req *http.Request, v interface{}, body []byte, cookie string, err error, inputOffset int64) *DecodeError {
+	decodeErr := &DecodeError{
+		Type: fmt.Sprintf("%T", v),
+		Path: req.URL.Path,
+		Body: truncate(redactCookie(string(body), cookie), maxDecodeErrorBody),
+		Err:  err,
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		decodeErr.Offset = typeErr.Offset
+		decodeErr.Field = typeErr.Field
+	case errors.As(err, &syntaxErr):
+		decodeErr.Offset = syntaxErr.Offset
+	}
+	// errors returned by UnmarshalJSON methods carry no position, or one relative to the value the
+	// method decoded, depending on the version of encoding/json
+	if syntaxErr == nil {
+		if field, offset, ok := locateDecodeError(body, reflect.TypeOf(v), "", 0); ok {
+			decodeErr.Field = field
+			decodeErr.Offset = offset
+		}
+	}
+	if decodeErr.Offset == 0 {
+		decodeErr.Offset = inputOffset
+	}
+	if decodeErr.Offset == 0 && errors.Is(err, io.ErrUnexpectedEOF) {
+		decodeErr.Offset = int64(len(body))
+	}
+
+	return decodeErr
+}
+
+// locateDecodeError finds the innermost value of data that fails to decode into its part of type t.
+// It returns the dotted field path, in the format of json.UnmarshalTypeError.Field, and the offset
+// of the start of the value relative to base.
+func locateDecodeError(data []byte, t reflect.Type, path string, base int64) (string, int64, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// the concrete type behind an interface is only known to the UnmarshalJSON method of its parent
+	if t.Kind() == reflect.Interface || json.Unmarshal(data, reflect.New(t).Interface()) == nil {
+		return "", 0, false
+	}
+
+	var found bool
+	var field string
+	var offset int64
+	forEachJSONElement(data, func(key string, value []byte, valueOffset int64) bool {
+		var elem reflect.Type
+		switch {
+		case t.Kind() == reflect.Struct && key != "":
+			elem = jsonFieldType(t, key)
+		case t.Kind() == reflect.Map && key != "", t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+			elem = t.Elem()
+		}
+		if elem == nil {
+			return true
+		}
+
+		elemPath := path
+		if t.Kind() == reflect.Struct {
+			elemPath = joinFieldPath(path, key)
+		}
+		field, offset, found = locateDecodeError(value, elem, elemPath, base+valueOffset)
+		return !found
+	})
+	if found {
+		return field, offset, true
+	}
+
+	return path, base, true
+}
+
+// forEachJSONElement calls fn with the key (empty for arrays), raw value and offset of each element
+// of the JSON object or array in data, until fn returns false
+func forEachJSONElement(data []byte, fn func(key string, value []byte, offset int64) bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '{' && delim != '[') {
+		return
+	}
+
+	for dec.More() {
+		key := ""
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return
+			}
+			key, _ = tok.(string)
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return
+		}
+		// InputOffset is the end of the value, which is the same length as value
+		offset := dec.InputOffset() - int64(len(value))
+		if !fn(key, value, offset) {
+			return
+		}
+	}
+}
+
+// jsonFieldType returns the type of the field of struct t that the JSON key decodes into, or nil
+func jsonFieldType(t reflect.Type, key string) reflect.Type {
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if ft := jsonFieldType(embedded, key); ft != nil {
+					return ft
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f.Type
+		}
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = f.Type
+		}
+	}
+	return fold
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// newUnmarshalTypeError is returned by UnmarshalJSON methods for values they can't decode into v.
+// encoding/json adds the path of the field being decoded to it, which ends up in DecodeError.Field.
+func newUnmarshalTypeError(data []byte, v interface{}) *json.UnmarshalTypeError {
+	return &json.UnmarshalTypeError{
+		Value: describeJSONValue(data),
+		Type:  reflect.TypeOf(v),
+	}
+}
+
+// withField prefixes the field path of an *json.UnmarshalTypeError returned while decoding field,
+// for UnmarshalJSON methods that decode some fields themselves
+func withField(err error, field string) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			typeErr.Field = field
+		} else {
+			typeErr.Field = field + "." + typeErr.Field
+		}
+	}
+	return err
+}
+
+// describeJSONValue describes a JSON value the way json.UnmarshalTypeError.Value does, eg `string "abc"`
+func describeJSONValue(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return "empty value"
+	}
+
+	switch data[0] {
+	case '"':
+		return "string " + truncate(string(data), 64)
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	}
+	return "number " + truncate(string(data), 64)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// don't cut a multi-byte character in half
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
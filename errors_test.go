@@ -0,0 +1,103 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantField string
+		wantValue string
+	}{
+		{"syntax", `{"user_asset_act":{"id":1,`, "", ""},
+		{"syntax error", `{"user_asset_act":{"id":1,,}}`, "", ","},
+		{"type", `{"user_asset_act":{"is_income":"yes"}}`, "user_asset_act.is_income", `"yes"`},
+		{"money", `{"user_asset_act":{"content":"abc","amount":"abc"}}`, "user_asset_act.amount", `"abc"`},
+		{"orig amount", `{"user_asset_act":{"currency":"USD","orig_amount":"abc"}}`, "user_asset_act.orig_amount", `"abc"`},
+		{"timestamp", `{"user_asset_act":{"updated_at":"yesterday"}}`, "user_asset_act.updated_at", `"yesterday"`},
+		{"string id", `{"user_asset_act":{"id":true}}`, "user_asset_act.id", `true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := c.GetUserAssetActivity(context.Background(), "1")
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("err = %v, want a *DecodeError", err)
+			}
+			if decodeErr.Offset <= 0 {
+				t.Errorf("Offset = %d, want > 0", decodeErr.Offset)
+			}
+			if decodeErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", decodeErr.Field, tt.wantField)
+			}
+			// encoding/json reports the end of a value, the locator the start
+			off, n := int(decodeErr.Offset), len(tt.wantValue)
+			if around := tt.body[max(off-n, 0):min(off+n, len(tt.body))]; !strings.Contains(around, tt.wantValue) {
+				t.Errorf("Offset = %d is at %q, want it at %q", off, around, tt.wantValue)
+			}
+			if decodeErr.Type != "*moneyforward.UserAssetActResponse" || decodeErr.Body != tt.body {
+				t.Errorf("DecodeError = %+v", decodeErr)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		status int
+		is     error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadGateway, ErrServer},
+	}
+
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, `{"error":"bad cookie session=secret-cookie-value"}`)
+		})
+
+		_, err := c.GetAccountSummaries(context.Background())
+		if !errors.Is(err, tt.is) {
+			t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.is)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+			t.Fatalf("status %d: err = %v, want an *APIError", tt.status, err)
+		}
+		if strings.Contains(apiErr.Body, "secret-cookie-value") {
+			t.Errorf("status %d: body contains the cookie: %s", tt.status, apiErr.Body)
+		}
+	}
+}
+
+func TestDecodeErrorInArray(t *testing.T) {
+	body := `{"user_asset_acts":[{"id":1,"amount":100},{"id":2,"amount":"1.2.3"}],"total_count":2}`
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	_, err := c.GetUserAssetActivities(context.Background(), UserAssetActsParams{})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("err = %v, want a *DecodeError", err)
+	}
+	if decodeErr.Field != "user_asset_acts.amount" || body[decodeErr.Offset:decodeErr.Offset+7] != `"1.2.3"` {
+		t.Errorf("Field = %q, Offset = %d, want user_asset_acts.amount at %d", decodeErr.Field, decodeErr.Offset, strings.Index(body, `"1.2.3"`))
+	}
+}
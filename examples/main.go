@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -11,20 +12,21 @@ func main() {
 	// Initialize client
 	cookieString := "" // Replace with your MF cookie string
 	client := moneyforward.NewClient(cookieString)
+	ctx := context.Background()
 
 	// Get account summaries
-	accounts, err := client.GetAccountSummaries()
+	accounts, err := client.GetAccountSummaries(ctx)
 	if err != nil {
 		log.Fatal("Failed to get accounts:", err)
 	}
 
 	// Print account balances
 	for _, account := range accounts.Accounts {
-		fmt.Printf("Account: %s, Balance: ¥%.2f\n", account.Name, account.Amount)
+		fmt.Printf("Account: %s, Balance: %s\n", account.Name, account.Amount.Display())
 	}
 
 	// Get recent transactions
-	transactions, err := client.GetUserAssetActivities(moneyforward.UserAssetActsParams{
+	transactions, err := client.GetUserAssetActivities(ctx, moneyforward.UserAssetActsParams{
 		Size:         16,
 		IsNew:        true,
 		IsContinuous: true,
@@ -36,15 +38,15 @@ func main() {
 	// Print recent transactions
 	fmt.Println("\nRecent Transactions:")
 	for _, act := range transactions.UserAssetActs {
-		fmt.Printf("%s: ¥%.2f - %s\n",
+		fmt.Printf("%s: %s - %s\n",
 			act.RecognizedAt.Format("2006-01-02"),
-			act.Amount,
+			act.Amount.Display(),
 			act.Content,
 		)
 	}
 
 	// Get home timeline
-	timeline, err := client.GetHomeTimeline(10)
+	timeline, err := client.GetHomeTimeline(ctx, 10)
 	if err != nil {
 		log.Fatal("Failed to get home timeline:", err)
 	}
@@ -52,28 +54,34 @@ func main() {
 	for _, t := range timeline.Timeline {
 		fmt.Printf("Date: %s\n", t.Date)
 		for _, card := range t.Cards {
-			if card.UserNotification != nil {
-				fmt.Printf("  - Notification: %+v\n", card.UserNotification.Parameters)
-			}
-			if card.HomeCard != nil {
+			switch card := card.(type) {
+			case *moneyforward.AggregationErrorCard:
+				fmt.Printf("  - Aggregation error: %s\n", card.Account.Name)
+			case *moneyforward.LargeSpendCard:
+				fmt.Printf("  - Largest spending: %s\n", card.Amount.Amount.Display())
+			case *moneyforward.SumAmountCard:
+				fmt.Printf("  - Total spending: %s\n", card.Amount.Amount.Display())
+			case *moneyforward.PromoBannerCard:
 				fmt.Printf("  - Card: %s (Valid: %s - %s)\n",
-					card.HomeCard.ID,
-					card.HomeCard.StartAt.Format("2006-01-02"),
-					card.HomeCard.EndAt.Format("2006-01-02"),
+					card.ID,
+					card.StartAt.Format("2006-01-02"),
+					card.EndAt.Format("2006-01-02"),
 				)
+			case *moneyforward.UnknownCard:
+				fmt.Printf("  - Unknown card: %s\n", card.Type)
 			}
 		}
 	}
 
 	// Force update
-	err = client.ForceUpdate()
+	err = client.ForceUpdate(ctx)
 	if err != nil {
 		log.Fatal("Failed to force update:", err)
 	}
 	fmt.Println("\nForced update successful")
 
 	// Get all transactions
-	allTransactions, err := client.GetTransactions()
+	allTransactions, err := client.GetTransactions(ctx)
 	if err != nil {
 		log.Fatal("Failed to get all transactions:", err)
 	}
@@ -86,7 +94,7 @@ func main() {
 		)
 	}
 
-	acts, err := client.GetUserAssetActivities(moneyforward.UserAssetActsParams{})
+	acts, err := client.GetUserAssetActivities(ctx, moneyforward.UserAssetActsParams{})
 	if err != nil {
 		log.Fatal("Failed to get user asset acts:", err)
 	}
@@ -95,25 +103,25 @@ func main() {
 		fmt.Println("\nLast 10 Transactions:")
 
 		for _, act := range acts.UserAssetActs {
-			fmt.Printf("  - %s: ¥%.2f (%s)\n", act.Content, act.Amount, act.RecognizedAt.Format("2006-01-02"))
+			fmt.Printf("  - %s: %s (%s)\n", act.Content, act.Amount.Display(), act.RecognizedAt.Format("2006-01-02"))
 		}
 	}
 
 	// Get specific account
-	account, err := client.GetAccount(accounts.Accounts[0].ShowPath)
+	account, err := client.GetAccount(ctx, accounts.Accounts[0].ShowPath)
 	if err != nil {
 		log.Fatal("Failed to get specific account:", err)
 	}
 	fmt.Println("\nAccount Details:")
 	fmt.Printf("  Name: %s\n", account.Account.DisplayName)
-	fmt.Printf("  Total Assets: ¥%.2f\n", account.Account.TotalAsset)
-	fmt.Printf("  Total Liabilities: ¥%.2f\n", account.Account.TotalLiability)
+	fmt.Printf("  Total Assets: %s\n", account.Account.TotalAsset.Display())
+	fmt.Printf("  Total Liabilities: %s\n", account.Account.TotalLiability.Display())
 	fmt.Printf("  Last Updated: %s\n", account.Account.LastAggregatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Println("  Sub Accounts:")
 	for _, sub := range account.Account.SubAccounts {
 		fmt.Printf("    - %s (%s)\n", sub.SubName, sub.SubType)
 		for _, summary := range sub.UserAssetDetSummaries {
-			fmt.Printf("      %s: ¥%.2f\n", summary.AssetSubclassName, summary.JPYValue)
+			fmt.Printf("      %s: %s\n", summary.AssetSubclassName, summary.JPYValue.Display())
 		}
 	}
 }
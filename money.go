@@ -0,0 +1,390 @@
+package moneyforward
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts that the API sends without a currency
+const DefaultCurrency = "JPY"
+
+var (
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies
+	ErrCurrencyMismatch = errors.New("moneyforward: currency mismatch")
+	// ErrOverflow is returned when the result of an operation doesn't fit into an int64 amount
+	ErrOverflow = errors.New("moneyforward: amount overflow")
+)
+
+// currencyExponents holds the number of decimal places of currencies that don't use 2
+var currencyExponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"CLP": 0,
+	"ISK": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+}
+
+// currencySymbols are used by Money.Display
+var currencySymbols = map[string]string{
+	"JPY": "¥",
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// CurrencyExponent returns the number of decimal places of an ISO 4217 currency.
+// An empty currency is DefaultCurrency.
+func CurrencyExponent(currency string) int {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money is an exact amount in the minor unit of its currency, eg yen for JPY and cents for USD.
+// An empty currency is DefaultCurrency, except that the zero value can be combined with any currency.
+type Money struct {
+	// Amount is the amount in minor units
+	Amount int64
+	// Currency is the ISO 4217 currency code
+	Currency string
+}
+
+// currency returns the currency of m, DefaultCurrency if it has none
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// NewMoney returns an amount of minor units, eg NewMoney(1234, "USD") is 12.34 USD
+func NewMoney(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: normalizeCurrency(currency)}
+}
+
+// JPY returns an amount of yen
+func JPY(yen int64) Money {
+	return NewMoney(yen, "JPY")
+}
+
+// NewMoneyFromFloat converts f to minor units of currency, rounding half away from zero
+func NewMoneyFromFloat(f float64, currency string) Money {
+	currency = normalizeCurrency(currency)
+	scale := math.Pow10(CurrencyExponent(currency))
+	return Money{Amount: int64(math.Round(f * scale)), Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "1234", "-12.345" or "1,234.5" in currency.
+// Digits beyond the precision of the currency are rounded half away from zero.
+func ParseMoney(s string, currency string) (Money, error) {
+	currency = normalizeCurrency(currency)
+	minor, err := parseDecimal(s, CurrencyExponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+const (
+	// maxInt64Digits is the number of digits of math.MaxInt64
+	maxInt64Digits = 19
+	// maxDecimalExponent bounds the exponent accepted by parseDecimal
+	maxDecimalExponent = 1000
+)
+
+// parseDecimal converts a decimal string to an integer scaled by 10^exp without going through float64
+func parseDecimal(s string, exp int) (int64, error) {
+	orig := s
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+
+	var mantissa string
+	var exponent int
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q", orig)
+		}
+		mantissa, exponent = s[:i], e
+	} else {
+		mantissa = s
+	}
+
+	neg := false
+	switch {
+	case strings.HasPrefix(mantissa, "-"):
+		neg = true
+		mantissa = mantissa[1:]
+	case strings.HasPrefix(mantissa, "+"):
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid amount %q", orig)
+	}
+
+	// drop leading zeros so that the number of digits before the point bounds the magnitude
+	trimmed := strings.TrimLeft(digits, "0")
+	if trimmed == "" {
+		return 0, nil
+	}
+	intLen := len(intPart) - (len(digits) - len(trimmed))
+	digits = trimmed
+
+	// exponents this large can't produce an int64 and would overflow the arithmetic below
+	if exponent > maxDecimalExponent {
+		return 0, fmt.Errorf("amount %q out of range", orig)
+	}
+	if exponent < -maxDecimalExponent {
+		return 0, nil
+	}
+
+	// position of the decimal point within digits after scaling by 10^exp
+	point := intLen + exponent + exp
+	if point < 0 {
+		return 0, nil
+	}
+	if point > maxInt64Digits {
+		return 0, fmt.Errorf("amount %q out of range", orig)
+	}
+	for len(digits) < point {
+		digits += "0"
+	}
+
+	whole := strings.TrimLeft(digits[:point], "0")
+	if whole == "" {
+		whole = "0"
+	}
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount %q out of range", orig)
+	}
+	if point < len(digits) && digits[point] >= '5' {
+		if n == math.MaxInt64 {
+			return 0, fmt.Errorf("amount %q out of range", orig)
+		}
+		n++
+	}
+
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// compatible returns the common currency of m and o
+func (m Money) compatible(o Money) (string, error) {
+	switch {
+	case m.Currency == "" && m.Amount == 0:
+		return o.currency(), nil
+	case o.Currency == "" && o.Amount == 0:
+		return m.currency(), nil
+	case m.currency() == o.currency():
+		return m.currency(), nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency(), o.currency())
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.compatible(o)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, o)
+	}
+	return Money{Amount: sum, Currency: currency}, nil
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) (Money, error) {
+	currency, err := m.compatible(o)
+	if err != nil {
+		return Money{}, err
+	}
+	diff := m.Amount - o.Amount
+	if (o.Amount > 0 && diff > m.Amount) || (o.Amount < 0 && diff < m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrOverflow, m, o)
+	}
+	return Money{Amount: diff, Currency: currency}, nil
+}
+
+// Mul returns m multiplied by n
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.Amount * n
+	if m.Amount != 0 && (product/m.Amount != n || (m.Amount == -1 && n == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrOverflow, m, n)
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Neg returns -m. The smallest int64 amount can't be negated and is returned unchanged.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Abs returns the absolute value of m, see Neg for the smallest int64 amount
+func (m Money) Abs() Money {
+	if m.Amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp compares m and o and returns -1, 0 or +1
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := m.compatible(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Equal reports whether m and o are the same amount in the same currency
+func (m Money) Equal(o Money) bool {
+	c, err := m.Cmp(o)
+	return err == nil && c == 0
+}
+
+// IsZero reports whether m is 0
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether m is greater than 0
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative reports whether m is less than 0
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Float64 returns m in major units, eg 12.34 for 1234 cents. The result may be inexact.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(CurrencyExponent(m.currency()))
+}
+
+// Decimal returns m in major units as an exact decimal string, eg "-12.34"
+func (m Money) Decimal() string {
+	return m.format(false)
+}
+
+// String returns m with its currency, eg "12.34 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currency()
+}
+
+// Display returns m with a currency symbol and thousands separators, eg "¥1,234" or "-$12.34"
+func (m Money) Display() string {
+	amount := m.format(true)
+	symbol, ok := currencySymbols[m.currency()]
+	if !ok {
+		return amount + " " + m.currency()
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "-" + symbol + amount[1:]
+	}
+	return symbol + amount
+}
+
+func (m Money) format(group bool) string {
+	exp := CurrencyExponent(m.currency())
+
+	abs := m.Amount
+	sign := ""
+	if abs < 0 {
+		abs = -abs
+		sign = "-"
+	}
+
+	digits := strconv.FormatInt(abs, 10)
+	for len(digits) <= exp {
+		digits = "0" + digits
+	}
+	whole, frac := digits[:len(digits)-exp], digits[len(digits)-exp:]
+
+	if group {
+		var b strings.Builder
+		for i, r := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteByte(',')
+			}
+			b.WriteRune(r)
+		}
+		whole = b.String()
+	}
+
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// MarshalJSON encodes m as a JSON number in major units, as the API expects
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string in DefaultCurrency.
+// Types that know the currency of an amount re-parse it with that currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalMoney(data, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// unmarshalMoney decodes a JSON number or numeric string in currency, null and empty strings decode to 0
+func unmarshalMoney(data []byte, currency string) (Money, error) {
+	zero := NewMoney(0, currency)
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return zero, nil
+	}
+
+	s := string(data)
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return Money{}, newUnmarshalTypeError(data, Money{})
+		}
+		if strings.TrimSpace(s) == "" {
+			return zero, nil
+		}
+	}
+
+	m, err := ParseMoney(s, currency)
+	if err != nil {
+		return Money{}, newUnmarshalTypeError(data, Money{})
+	}
+	return m, nil
+}
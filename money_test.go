@@ -0,0 +1,309 @@
+package moneyforward
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		exp     int
+		want    int64
+		wantErr bool
+	}{
+		{"1234", 0, 1234, false},
+		{"+1234", 0, 1234, false},
+		{"-1234", 0, -1234, false},
+		{" 1,234,567 ", 0, 1234567, false},
+		{"12.34", 2, 1234, false},
+		{"-12.34", 2, -1234, false},
+		{"12.3", 2, 1230, false},
+		{".5", 2, 50, false},
+		{"5.", 2, 500, false},
+		{"000012.34", 2, 1234, false},
+		{"0", 2, 0, false},
+		{"-0.00", 2, 0, false},
+
+		// half away from zero
+		{"12.345", 2, 1235, false},
+		{"12.344", 2, 1234, false},
+		{"-12.345", 2, -1235, false},
+		{"0.5", 0, 1, false},
+		{"-0.5", 0, -1, false},
+		{"0.49999", 0, 0, false},
+		{"0.005", 2, 1, false},
+		{"0.0049", 2, 0, false},
+
+		// exponents
+		{"1e3", 0, 1000, false},
+		{"1.5E2", 0, 150, false},
+		{"1.2345e1", 2, 1235, false},
+		{"12345e-2", 0, 123, false},
+		{"5e-1", 0, 1, false},
+		{"1e-5", 2, 0, false},
+		{"-2.5e+1", 0, -25, false},
+		{"1e-999999999999", 0, 0, false},
+
+		// overflow
+		{"9223372036854775807", 0, 9223372036854775807, false},
+		{"-9223372036854775807", 0, -9223372036854775807, false},
+		{"9223372036854775808", 0, 0, true},
+		{"9223372036854775807.5", 0, 0, true},
+		{"92233720368547758.08", 2, 0, true},
+		{"1e19", 0, 0, true},
+		{"1e999999999999", 0, 0, true},
+		{"0e999999999999", 0, 0, false},
+
+		// invalid
+		{"", 0, 0, true},
+		{"-", 0, 0, true},
+		{".", 0, 0, true},
+		{"abc", 0, 0, true},
+		{"1.2.3", 0, 0, true},
+		{"1e", 0, 0, true},
+		{"1e1.5", 0, 0, true},
+		{"--1", 0, 0, true},
+		{"¥100", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDecimal(tt.in, tt.exp)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDecimal(%q, %d) error = %v, wantErr %v", tt.in, tt.exp, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDecimal(%q, %d) = %d, want %d", tt.in, tt.exp, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{`1234`, JPY(1234), false},
+		{`-1234`, JPY(-1234), false},
+		{`1234.5`, JPY(1235), false},
+		{`1.2345e3`, JPY(1235), false},
+		{`"1234"`, JPY(1234), false},
+		{`"1,234"`, JPY(1234), false},
+		{`" -12 "`, JPY(-12), false},
+		{`""`, JPY(0), false},
+		{`"  "`, JPY(0), false},
+		{`null`, JPY(0), false},
+		{`"abc"`, Money{}, true},
+		{`true`, Money{}, true},
+		{`{}`, Money{}, true},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Unmarshal(%s) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{JPY(1234), `1234`},
+		{JPY(-1234), `-1234`},
+		{Money{Amount: 500}, `500`},
+		{Money{}, `0`},
+		{NewMoney(1234, "USD"), `12.34`},
+		{NewMoney(-5, "USD"), `-0.05`},
+		{NewMoney(1234, "KWD"), `1.234`},
+	}
+
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%#v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		in                    Money
+		decimal, str, display string
+	}{
+		{JPY(1234567), "1234567", "1234567 JPY", "¥1,234,567"},
+		{JPY(-1234), "-1234", "-1234 JPY", "-¥1,234"},
+		{Money{Amount: 500}, "500", "500 JPY", "¥500"},
+		{NewMoney(123456, "USD"), "1234.56", "1234.56 USD", "$1,234.56"},
+		{NewMoney(-5, "USD"), "-0.05", "-0.05 USD", "-$0.05"},
+		{NewMoney(1234, "CHF"), "12.34", "12.34 CHF", "12.34 CHF"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.Decimal(); got != tt.decimal {
+			t.Errorf("%#v.Decimal() = %q, want %q", tt.in, got, tt.decimal)
+		}
+		if got := tt.in.String(); got != tt.str {
+			t.Errorf("%#v.String() = %q, want %q", tt.in, got, tt.str)
+		}
+		if got := tt.in.Display(); got != tt.display {
+			t.Errorf("%#v.Display() = %q, want %q", tt.in, got, tt.display)
+		}
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	tests := []struct {
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{JPY(1), JPY(2), JPY(3), nil},
+		{Money{}, NewMoney(100, "USD"), NewMoney(100, "USD"), nil},
+		{NewMoney(100, "USD"), Money{}, NewMoney(100, "USD"), nil},
+		{Money{Amount: 100}, JPY(1), JPY(101), nil},
+		{Money{Amount: 100}, NewMoney(1, "USD"), Money{}, ErrCurrencyMismatch},
+		{JPY(1), NewMoney(1, "USD"), Money{}, ErrCurrencyMismatch},
+		{JPY(math.MaxInt64 - 1), JPY(1), JPY(math.MaxInt64), nil},
+		{JPY(math.MaxInt64), JPY(1), Money{}, ErrOverflow},
+		{JPY(math.MinInt64), JPY(-1), Money{}, ErrOverflow},
+		{JPY(math.MinInt64), JPY(math.MaxInt64), JPY(-1), nil},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.a.Add(tt.b)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%v.Add(%v) error = %v, want %v", tt.a, tt.b, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v.Add(%v) = %#v, want %#v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMoneySub(t *testing.T) {
+	tests := []struct {
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{JPY(1), JPY(3), JPY(-2), nil},
+		{Money{}, NewMoney(100, "USD"), NewMoney(-100, "USD"), nil},
+		{JPY(1), NewMoney(1, "USD"), Money{}, ErrCurrencyMismatch},
+		{JPY(-1), JPY(math.MinInt64 + 1), JPY(math.MaxInt64 - 1), nil},
+		{JPY(0), JPY(math.MinInt64), Money{}, ErrOverflow},
+		{JPY(math.MinInt64), JPY(1), Money{}, ErrOverflow},
+		{JPY(math.MaxInt64), JPY(-1), Money{}, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.a.Sub(tt.b)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%v.Sub(%v) error = %v, want %v", tt.a, tt.b, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v.Sub(%v) = %#v, want %#v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		m       Money
+		n       int64
+		want    Money
+		wantErr error
+	}{
+		{NewMoney(150, "USD"), 3, NewMoney(450, "USD"), nil},
+		{JPY(-2), -4, JPY(8), nil},
+		{JPY(0), math.MaxInt64, JPY(0), nil},
+		{JPY(math.MaxInt64), -1, JPY(-math.MaxInt64), nil},
+		{JPY(math.MaxInt64/2 + 1), 2, Money{}, ErrOverflow},
+		{JPY(math.MinInt64), -1, Money{}, ErrOverflow},
+		{JPY(-1), math.MinInt64, Money{}, ErrOverflow},
+		{JPY(1 << 32), 1 << 32, Money{}, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.m.Mul(tt.n)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%v.Mul(%d) error = %v, want %v", tt.m, tt.n, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v.Mul(%d) = %#v, want %#v", tt.m, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestUserAssetActUnmarshalJSONCurrency(t *testing.T) {
+	tests := []struct {
+		in                 string
+		amount, origAmount Money
+	}{
+		{`{"amount":-1500,"orig_amount":-1500}`, JPY(-1500), JPY(-1500)},
+		{`{"amount":-1500,"orig_amount":-1500,"currency":"JPY"}`, JPY(-1500), JPY(-1500)},
+		{`{"amount":-1580,"orig_amount":-10.5,"currency":"USD"}`, JPY(-1580), NewMoney(-1050, "USD")},
+		{`{"amount":-1580,"orig_amount":"-10.505","currency":"usd"}`, JPY(-1580), NewMoney(-1051, "USD")},
+		{`{"amount":"3","orig_amount":"0.021","currency":"KWD"}`, JPY(3), NewMoney(21, "KWD")},
+		{`{"amount":0,"orig_amount":null,"currency":"EUR"}`, JPY(0), NewMoney(0, "EUR")},
+		{`{"amount":0,"currency":"EUR"}`, JPY(0), NewMoney(0, "EUR")},
+	}
+
+	for _, tt := range tests {
+		var act UserAssetAct
+		if err := json.Unmarshal([]byte(tt.in), &act); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", tt.in, err)
+			continue
+		}
+		if act.Amount != tt.amount {
+			t.Errorf("Unmarshal(%s).Amount = %#v, want %#v", tt.in, act.Amount, tt.amount)
+		}
+		if act.OrigAmount != tt.origAmount {
+			t.Errorf("Unmarshal(%s).OrigAmount = %#v, want %#v", tt.in, act.OrigAmount, tt.origAmount)
+		}
+	}
+
+	var act UserAssetAct
+	if err := json.Unmarshal([]byte(`{"orig_amount":"x","currency":"USD"}`), &act); err == nil {
+		t.Error("Unmarshal with invalid orig_amount succeeded")
+	}
+}
+
+func TestUserAssetDetUnmarshalJSONCurrency(t *testing.T) {
+	tests := []struct {
+		in                  string
+		value, profit, cost Money
+	}{
+		{`{"value":100000,"profit":5000,"cost":95000}`, JPY(100000), JPY(5000), JPY(95000)},
+		{`{"value":1234.56,"profit":"-12.3","cost":"1,246.86","currency":"USD"}`, NewMoney(123456, "USD"), NewMoney(-1230, "USD"), NewMoney(124686, "USD")},
+		{`{"value":"","profit":null,"currency":"EUR"}`, NewMoney(0, "EUR"), NewMoney(0, "EUR"), NewMoney(0, "EUR")},
+	}
+
+	for _, tt := range tests {
+		var det UserAssetDet
+		if err := json.Unmarshal([]byte(tt.in), &det); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", tt.in, err)
+			continue
+		}
+		if det.Value != tt.value || det.Profit != tt.profit || det.Cost != tt.cost {
+			t.Errorf("Unmarshal(%s) = %#v, %#v, %#v, want %#v, %#v, %#v", tt.in, det.Value, det.Profit, det.Cost, tt.value, tt.profit, tt.cost)
+		}
+	}
+}
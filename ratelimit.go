@@ -0,0 +1,113 @@
+package moneyforward
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerSecond = 2
+	defaultBurst             = 5
+	defaultMaxConcurrency    = 4
+)
+
+// WithRateLimit limits the client to requestsPerSecond with bursts of up to burst requests,
+// shared across all goroutines using the client. A requestsPerSecond <= 0 disables the limit.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.limiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+// WithMaxConcurrency limits the number of requests in flight at the same time.
+// A n <= 0 disables the limit.
+func WithMaxConcurrency(n int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.concurrency = newSemaphore(n)
+	}
+}
+
+// rateLimiter is a token bucket that is refilled at rate tokens per second
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	burst = max(burst, 1)
+
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// take the token now, callers that have to wait queue up behind each other
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// semaphore limits the number of concurrent requests
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s <- struct{}{}:
+		return nil
+	}
+}
+
+func (s semaphore) release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
@@ -0,0 +1,137 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		rps        float64
+		burst      int
+		requests   int
+		concurrent bool
+		min, max   time.Duration
+	}{
+		{"disabled", 0, 0, 10, false, 0, 500 * time.Millisecond},
+		{"within burst", 20, 5, 5, false, 0, 40 * time.Millisecond},
+		{"beyond burst", 20, 2, 6, false, 190 * time.Millisecond, 600 * time.Millisecond},
+		{"shared across goroutines", 20, 2, 6, true, 190 * time.Millisecond, 600 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{}`)
+			}, WithRateLimit(tt.rps, tt.burst))
+
+			start := time.Now()
+			var wg sync.WaitGroup
+			errs := make(chan error, tt.requests)
+			for i := 0; i < tt.requests; i++ {
+				call := func() {
+					defer wg.Done()
+					_, err := c.GetAccountSummaries(context.Background())
+					errs <- err
+				}
+				wg.Add(1)
+				if tt.concurrent {
+					go call()
+				} else {
+					call()
+				}
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed < tt.min || elapsed > tt.max {
+				t.Errorf("%d requests took %v, want between %v and %v", tt.requests, elapsed, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestClientRateLimitContextCanceled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}, WithRateLimit(1, 1))
+
+	if _, err := c.GetAccountSummaries(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetAccountSummaries(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// the canceled request gives its token back, so the next one waits for a single token
+	start := time.Now()
+	if _, err := c.GetAccountSummaries(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("request after cancellation took %v, want at most 1s", elapsed)
+	}
+}
+
+func TestClientMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		requests int
+		wantMax  int64
+	}{
+		{"one", 1, 4, 1},
+		{"two", 2, 8, 2},
+		// without a limit all requests are sent at once, but they aren't guaranteed to overlap
+		{"disabled", 0, 6, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int64
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+				fmt.Fprint(w, `{}`)
+			}, WithMaxConcurrency(tt.n))
+
+			var wg sync.WaitGroup
+			for i := 0; i < tt.requests; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := c.GetAccountSummaries(context.Background()); err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := maxInFlight.Load(); got != tt.wantMax && (tt.n > 0 || got < tt.wantMax) {
+				t.Errorf("max requests in flight = %d, want %d", got, tt.wantMax)
+			}
+		})
+	}
+}
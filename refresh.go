@@ -0,0 +1,149 @@
+package moneyforward
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultRefreshConcurrency = 2
+
+// RefreshOptions configures RefreshAll
+type RefreshOptions struct {
+	// Concurrency is the number of accounts aggregated at the same time, defaults to 2
+	Concurrency int
+	// Aggregate configures polling of each account
+	Aggregate AggregateOptions
+}
+
+// RefreshResult is the outcome of refreshing a single account
+type RefreshResult struct {
+	AccountIDHash string
+	Name          string
+	Outcome       AggregationOutcome
+	// RequiresUserOperation is set for accounts that can't be aggregated without the user, eg to enter a one-time password
+	RequiresUserOperation bool
+	// Aggregation is nil if no aggregation was triggered
+	Aggregation *AggregationResult
+	// Err is set when the account could not be refreshed because of a request error
+	Err error
+}
+
+// RefreshReport lists the outcome of RefreshAll per account, in the order of the account summaries
+type RefreshReport struct {
+	Results  []RefreshResult
+	Duration time.Duration
+}
+
+// RefreshAll triggers an aggregation of every linked account and waits for all of them to finish.
+// Accounts that require user operation are not aggregated but reported in NeedsUserAction.
+// Errors of single accounts are recorded in the report, only a failure to list the accounts is returned.
+func (c *Client) RefreshAll(ctx context.Context, refreshOpts RefreshOptions, opts ...RequestOption) (*RefreshReport, error) {
+	start := time.Now()
+
+	summaries, err := c.GetAccountSummaries(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := refreshOpts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultRefreshConcurrency
+	}
+
+	report := &RefreshReport{
+		Results: make([]RefreshResult, len(summaries.Accounts)),
+	}
+
+	sem := newSemaphore(concurrency)
+	var wg sync.WaitGroup
+	for i, account := range summaries.Accounts {
+		report.Results[i] = RefreshResult{
+			AccountIDHash: account.AccountIDHash,
+			Name:          account.Name,
+		}
+
+		if err := sem.acquire(ctx); err != nil {
+			report.Results[i].Outcome = AggregationSkipped
+			report.Results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *RefreshResult, showPath MFShowPath) {
+			defer wg.Done()
+			defer sem.release()
+
+			c.refreshAccount(ctx, result, showPath, refreshOpts.Aggregate, opts...)
+		}(&report.Results[i], account.ShowPath)
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func (c *Client) refreshAccount(ctx context.Context, result *RefreshResult, showPath MFShowPath, aggOpts AggregateOptions, opts ...RequestOption) {
+	if showPath != "" {
+		account, err := c.GetAccount(ctx, showPath, opts...)
+		if err != nil {
+			result.Outcome = AggregationFailed
+			result.Err = err
+			return
+		}
+
+		if account.Account.Service.RequiresUserOperation {
+			result.Outcome = AggregationSkipped
+			result.RequiresUserOperation = true
+			return
+		}
+	}
+
+	aggregation, err := c.AggregateAndWait(ctx, result.AccountIDHash, aggOpts, opts...)
+	if err != nil {
+		result.Outcome = AggregationFailed
+		result.Err = err
+		return
+	}
+
+	result.Outcome = aggregation.Outcome
+	result.Aggregation = aggregation
+}
+
+// Succeeded returns the accounts that were aggregated successfully
+func (r *RefreshReport) Succeeded() []RefreshResult {
+	return r.filter(func(result RefreshResult) bool {
+		return result.Outcome == AggregationSucceeded
+	})
+}
+
+// Failed returns the accounts whose aggregation failed or could not be triggered
+func (r *RefreshReport) Failed() []RefreshResult {
+	return r.filter(func(result RefreshResult) bool {
+		return result.Outcome == AggregationFailed
+	})
+}
+
+// TimedOut returns the accounts whose aggregation did not finish in time
+func (r *RefreshReport) TimedOut() []RefreshResult {
+	return r.filter(func(result RefreshResult) bool {
+		return result.Outcome == AggregationTimedOut
+	})
+}
+
+// NeedsUserAction returns the accounts that can only be aggregated by the user
+func (r *RefreshReport) NeedsUserAction() []RefreshResult {
+	return r.filter(func(result RefreshResult) bool {
+		return result.RequiresUserOperation
+	})
+}
+
+func (r *RefreshReport) filter(keep func(RefreshResult) bool) []RefreshResult {
+	var results []RefreshResult
+	for _, result := range r.Results {
+		if keep(result) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
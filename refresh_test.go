@@ -0,0 +1,110 @@
+package moneyforward
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRefreshAll(t *testing.T) {
+	before := fakeAccountState{aggregatedAt: "2024-01-15T09:00:00+09:00", succeededAt: "2024-01-15T09:00:00+09:00"}
+	succeeded := fakeAccountState{aggregatedAt: "2024-01-15T10:00:00+09:00", succeededAt: "2024-01-15T10:00:00+09:00"}
+	failed := fakeAccountState{aggregatedAt: "2024-01-15T10:00:00+09:00", succeededAt: before.succeededAt, errorID: 4}
+
+	c := newTestClient(t, aggregationServer(t,
+		&fakeAccount{hash: "ok", before: before, after: &succeeded},
+		&fakeAccount{hash: "error", before: before, after: &failed},
+		&fakeAccount{hash: "otp", before: before, after: &succeeded, requiresUserOperation: true},
+		&fakeAccount{hash: "slow", before: before},
+		&fakeAccount{hash: "busy", before: before, triggerStatus: http.StatusConflict},
+	))
+
+	report, err := c.RefreshAll(context.Background(), RefreshOptions{
+		Concurrency: 3,
+		Aggregate:   AggregateOptions{PollInterval: time.Millisecond, Timeout: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hash            string
+		outcome         AggregationOutcome
+		userOperation   bool
+		wantAggregation bool
+		wantErr         bool
+	}{
+		{"ok", AggregationSucceeded, false, true, false},
+		{"error", AggregationFailed, false, true, false},
+		{"otp", AggregationSkipped, true, false, false},
+		{"slow", AggregationTimedOut, false, true, false},
+		{"busy", AggregationFailed, false, false, true},
+	}
+	if len(report.Results) != len(tests) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(tests))
+	}
+	for i, tt := range tests {
+		got := report.Results[i]
+		if got.AccountIDHash != tt.hash || got.Name != "Account "+tt.hash {
+			t.Errorf("result %d is %s (%s), want %s in the order of the summaries", i, got.AccountIDHash, got.Name, tt.hash)
+			continue
+		}
+		if got.Outcome != tt.outcome || got.RequiresUserOperation != tt.userOperation {
+			t.Errorf("%s: outcome = %q, requires user operation = %v, want %q, %v", tt.hash, got.Outcome, got.RequiresUserOperation, tt.outcome, tt.userOperation)
+		}
+		if (got.Aggregation != nil) != tt.wantAggregation {
+			t.Errorf("%s: Aggregation = %+v, want set %v", tt.hash, got.Aggregation, tt.wantAggregation)
+		}
+		if (got.Err != nil) != tt.wantErr {
+			t.Errorf("%s: Err = %v, want error %v", tt.hash, got.Err, tt.wantErr)
+		}
+	}
+
+	filters := []struct {
+		name string
+		got  []RefreshResult
+		want []string
+	}{
+		{"Succeeded", report.Succeeded(), []string{"ok"}},
+		{"Failed", report.Failed(), []string{"error", "busy"}},
+		{"TimedOut", report.TimedOut(), []string{"slow"}},
+		{"NeedsUserAction", report.NeedsUserAction(), []string{"otp"}},
+	}
+	for _, f := range filters {
+		var hashes []string
+		for _, result := range f.got {
+			hashes = append(hashes, result.AccountIDHash)
+		}
+		if !slices.Equal(hashes, f.want) {
+			t.Errorf("%s() = %v, want %v", f.name, hashes, f.want)
+		}
+	}
+}
+
+func TestRefreshAllContextCanceled(t *testing.T) {
+	before := fakeAccountState{aggregatedAt: "2024-01-15T09:00:00+09:00"}
+	c := newTestClient(t, aggregationServer(t,
+		&fakeAccount{hash: "a", before: before},
+		&fakeAccount{hash: "b", before: before},
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := c.RefreshAll(ctx, RefreshOptions{
+		Concurrency: 1,
+		Aggregate:   AggregateOptions{PollInterval: time.Millisecond, Timeout: time.Minute},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := report.Results[0]; got.Outcome != AggregationFailed || got.Err == nil {
+		t.Errorf("running account: %+v, want failed with the context error", got)
+	}
+	if got := report.Results[1]; got.Outcome != AggregationSkipped || got.Err == nil {
+		t.Errorf("waiting account: %+v, want skipped with the context error", got)
+	}
+}
@@ -0,0 +1,152 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of failed requests.
+// Retries are only applied to idempotent requests, which are GET requests unless
+// overridden per call with WithIdempotent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for every following retry.
+	// Defaults to DefaultRetryPolicy.BaseDelay when zero.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts, defaults to DefaultRetryPolicy.MaxDelay when zero.
+	// Requests aren't retried when the server asks to wait longer with Retry-After.
+	MaxDelay time.Duration
+	// RetryableStatusCodes are the response status codes that trigger a retry.
+	// Defaults to 429, 500, 502, 503 and 504 when nil.
+	RetryableStatusCodes []int
+	// OnRetry is called before sleeping for the next attempt
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	Method string
+	Path   string
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int
+	// Delay is the time waited before the next attempt
+	Delay time.Duration
+	Err   error
+}
+
+// DefaultRetryPolicy retries transient failures up to 3 times with exponential backoff
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// WithRetryPolicy enables automatic retries of idempotent requests
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client.retryPolicy = &policy
+	}
+}
+
+// shouldRetry reports whether err is a transient failure
+func (p *RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// transport errors such as connection resets
+		return true
+	}
+
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the time to wait after the given failed attempt.
+// A Retry-After from the server takes precedence when it is longer than the backoff, ok is false
+// when it is longer than MaxDelay, in which case the request shouldn't be retried.
+// A zero BaseDelay or MaxDelay falls back to the one of DefaultRetryPolicy.
+func (p *RetryPolicy) delay(attempt int, retryAfter time.Duration) (d time.Duration, ok bool) {
+	base, maxDelay := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if retryAfter > maxDelay {
+		return 0, false
+	}
+
+	backoff := base << (attempt - 1)
+	// the shift overflows to <= 0 for large attempts
+	if backoff <= 0 || backoff > maxDelay {
+		backoff = maxDelay
+	}
+
+	// equal jitter: keep half of the backoff and randomize the other half
+	if half := backoff / 2; half > 0 {
+		backoff = half + rand.N(half)
+	}
+
+	if retryAfter > backoff {
+		return retryAfter, true
+	}
+	return backoff, true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+		ok         bool
+	}{
+		{"first attempt", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 1, 0, 500 * time.Millisecond, time.Second, true},
+		{"doubles", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 3, 0, 2 * time.Second, 4 * time.Second, true},
+		{"capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}, 5, 0, 1500 * time.Millisecond, 3 * time.Second, true},
+		{"overflow", RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}, 100, 0, 1500 * time.Millisecond, 3 * time.Second, true},
+		{"zero delays use defaults", RetryPolicy{MaxAttempts: 5}, 1, 0, DefaultRetryPolicy.BaseDelay / 2, DefaultRetryPolicy.BaseDelay, true},
+		{"zero max delay uses default", RetryPolicy{BaseDelay: time.Second}, 100, 0, DefaultRetryPolicy.MaxDelay / 2, DefaultRetryPolicy.MaxDelay, true},
+		{"retry after wins", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 1, 10 * time.Second, 10 * time.Second, 10 * time.Second, true},
+		{"retry after at max delay", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 1, time.Minute, time.Minute, time.Minute, true},
+		{"retry after above max delay", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 1, 24 * time.Hour, 0, 0, false},
+		{"retry after above default max delay", RetryPolicy{}, 1, time.Hour, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got, ok := tt.policy.delay(tt.attempt, tt.retryAfter)
+				if ok != tt.ok {
+					t.Fatalf("delay(%d, %v) ok = %v, want %v", tt.attempt, tt.retryAfter, ok, tt.ok)
+				}
+				if got < tt.min || got > tt.max {
+					t.Fatalf("delay(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+	get := func(c *Client, opts ...RequestOption) error {
+		_, err := c.GetAccountSummaries(context.Background(), opts...)
+		return err
+	}
+	put := func(c *Client, opts ...RequestOption) error {
+		_, err := c.UpdateUserAssetAct(context.Background(), "1", UserAssetActUpdates{Content: "lunch"}, opts...)
+		return err
+	}
+	post := func(c *Client, opts ...RequestOption) error {
+		return c.TriggerAccountAggregation(context.Background(), "abc", opts...)
+	}
+
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		call         func(*Client, ...RequestOption) error
+		opts         []RequestOption
+		statuses     []int
+		retryAfter   string
+		wantAttempts int
+		wantErr      error
+	}{
+		{"success", &fast, get, nil, nil, "", 1, nil},
+		{"no policy", nil, get, nil, []int{503}, "", 1, ErrServer},
+		{"retries get", &fast, get, nil, []int{503, 502}, "", 3, nil},
+		{"gives up after max attempts", &fast, get, nil, []int{503, 503, 503, 503}, "", 3, ErrServer},
+		{"retries 429", &fast, get, nil, []int{429}, "", 2, nil},
+		{"doesn't retry 404", &fast, get, nil, []int{404}, "", 1, ErrNotFound},
+		{"doesn't retry custom codes", &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryableStatusCodes: []int{502}}, get, nil, []int{503}, "", 1, ErrServer},
+		{"doesn't retry post", &fast, post, nil, []int{503}, "", 1, ErrServer},
+		{"retries idempotent post", &fast, post, []RequestOption{WithIdempotent(true)}, []int{503}, "", 2, nil},
+		{"doesn't retry non-idempotent get", &fast, get, []RequestOption{WithIdempotent(false)}, []int{503}, "", 1, ErrServer},
+		{"resends body", &fast, put, []RequestOption{WithIdempotent(true)}, []int{503, 503}, "", 3, nil},
+		{"honors retry after", &fast, get, nil, []int{429}, "1", 2, nil},
+		{"retry after above max delay", &fast, get, nil, []int{429}, "3600", 1, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			var bodies []string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+
+				if attempts <= len(tt.statuses) {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.statuses[attempts-1])
+					return
+				}
+				fmt.Fprint(w, `{}`)
+			})
+
+			var events []RetryEvent
+			if tt.policy != nil {
+				policy := *tt.policy
+				policy.OnRetry = func(e RetryEvent) { events = append(events, e) }
+				c.retryPolicy = &policy
+			}
+
+			start := time.Now()
+			err := tt.call(c, tt.opts...)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if len(events) != attempts-1 {
+				t.Errorf("OnRetry called %d times, want %d", len(events), attempts-1)
+			}
+			for i, e := range events {
+				if e.Attempt != i+1 || e.Err == nil || e.Path == "" || e.Method == "" {
+					t.Errorf("events[%d] = %+v", i, e)
+				}
+			}
+			for _, body := range bodies[1:] {
+				if body != bodies[0] {
+					t.Errorf("retried body = %q, want %q", body, bodies[0])
+				}
+			}
+			if tt.retryAfter == "1" && (time.Since(start) < time.Second || events[0].Delay < time.Second) {
+				t.Errorf("retried after %v with delay %v, want at least 1s", time.Since(start), events[0].Delay)
+			}
+		})
+	}
+}
+
+func TestClientRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second}))
+
+	if _, err := c.GetAccountSummaries(ctx); err == nil {
+		t.Fatal("err = nil, want an error")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
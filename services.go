@@ -0,0 +1,173 @@
+package moneyforward
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListServiceCategories gets the categories of linkable services
+func (c *Client) ListServiceCategories(ctx context.Context, opts ...RequestOption) (*ServiceCategoriesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/service_categories", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ServiceCategoriesResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListServices gets the linkable services of a service category
+func (c *Client) ListServices(ctx context.Context, categoryID int, opts ...RequestOption) (*ServicesResponse, error) {
+	req, err := c.newRequest(ctx, "GET", "/sp2/services", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"service_category_id": strconv.Itoa(categoryID),
+	}
+	c.addQueryParams(req, params)
+
+	var resp ServicesResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Search returns the services whose name or yomigana matches query, best matches first.
+// Matching ignores case, full-width/half-width differences and katakana/hiragana differences,
+// and also accepts the query's characters appearing in order with gaps.
+func (r *ServicesResponse) Search(query string) []Service {
+	return SearchServices(r.Services, query)
+}
+
+// SearchServices returns the services whose name or yomigana matches query, best matches first
+func SearchServices(services []Service, query string) []Service {
+	q := normalizeSearchText(query)
+	if q == "" {
+		return nil
+	}
+
+	type match struct {
+		service Service
+		score   int
+	}
+	var matches []match
+	for _, s := range services {
+		score := max(matchScore(normalizeSearchText(s.Name), q), matchScore(normalizeSearchText(s.Yomigana), q))
+		if score > 0 {
+			matches = append(matches, match{s, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]Service, len(matches))
+	for i, m := range matches {
+		result[i] = m.service
+	}
+	return result
+}
+
+// matchScore rates how well s matches q, 0 means no match
+func matchScore(s, q string) int {
+	switch {
+	case s == "":
+		return 0
+	case s == q:
+		return 4
+	case strings.HasPrefix(s, q):
+		return 3
+	case strings.Contains(s, q):
+		return 2
+	case isSubsequence(s, q):
+		return 1
+	}
+	return 0
+}
+
+// isSubsequence reports whether all runes of q appear in s in order
+func isSubsequence(s, q string) bool {
+	qr := []rune(q)
+	i := 0
+	for _, r := range s {
+		if i < len(qr) && r == qr[i] {
+			i++
+		}
+	}
+	return i == len(qr)
+}
+
+// halfwidthKatakana holds the full-width forms of U+FF61 to U+FF9F, the last two being the
+// combining voiced and semi-voiced sound marks
+const halfwidthKatakana = "。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン\u3099\u309a"
+
+// voicedKana maps katakana to their voiced form, eg カ to ガ
+var voicedKana = map[rune]rune{'ウ': 'ヴ'}
+
+// semiVoicedKana maps katakana to their semi-voiced form, eg ハ to パ
+var semiVoicedKana = map[rune]rune{}
+
+func init() {
+	for _, r := range "カキクケコサシスセソタチツテトハヒフヘホ" {
+		voicedKana[r] = r + 1
+	}
+	for _, r := range "ハヒフヘホ" {
+		semiVoicedKana[r] = r + 2
+	}
+}
+
+// normalizeSearchText lowercases s, folds full-width ASCII and half-width katakana to their
+// usual width, converts katakana to hiragana and removes whitespace
+func normalizeSearchText(s string) string {
+	halfwidth := []rune(halfwidthKatakana)
+
+	// fold widths first so that separate sound marks can be combined with the preceding kana
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == ' ' || r == '　' || r == '\t':
+			continue
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		case r >= 0xFF61 && r <= 0xFF9F:
+			r = halfwidth[r-0xFF61]
+		}
+
+		if n := len(runes); n > 0 {
+			prev := runes[n-1]
+			switch r {
+			case '\u3099', '゛':
+				if v, ok := voicedKana[prev]; ok {
+					runes[n-1] = v
+					continue
+				}
+			case '\u309a', '゜':
+				if v, ok := semiVoicedKana[prev]; ok {
+					runes[n-1] = v
+					continue
+				}
+			}
+		}
+		runes = append(runes, r)
+	}
+
+	var b strings.Builder
+	for _, r := range runes {
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 0x60
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
@@ -0,0 +1,37 @@
+package moneyforward
+
+import "testing"
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Mizuho Bank", "mizuhobank"},
+		{"ＭＩＺＵＨＯ", "mizuho"},
+		{"ミズホ", "みずほ"},
+		{"ﾐｽﾞﾎ", "みずほ"},
+		{"ﾊﾟﾙｺ", "ぱるこ"},
+		{"ｳﾞｨｰﾅｽ", "ゔぃーなす"},
+		{"ミス゛ホ", "みずほ"},
+		{"ﾞｱ", "゙あ"},
+		{"三井　住友", "三井住友"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeSearchText(tt.in); got != tt.want {
+			t.Errorf("normalizeSearchText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearchServicesHalfwidthKatakana(t *testing.T) {
+	services := []Service{
+		{ID: 1, Name: "みずほ銀行", Yomigana: "みずほぎんこう"},
+		{ID: 2, Name: "三井住友銀行", Yomigana: "みついすみともぎんこう"},
+	}
+
+	got := SearchServices(services, "ﾐｽﾞﾎ")
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("SearchServices() = %+v, want the service with ID 1", got)
+	}
+}
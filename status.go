@@ -0,0 +1,196 @@
+package moneyforward
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AccountStatus is the aggregation status of a linked account as sent by the API.
+// The API sends it as a number or a string, both are accepted. Numeric values are kept in
+// decimal form, anything else as the raw text, so no information is lost.
+type AccountStatus string
+
+// MoneyForward doesn't document the status codes, the names below are best-effort and the API
+// may send codes that aren't listed
+const (
+	AccountStatusOK       AccountStatus = "0"
+	AccountStatusUpdating AccountStatus = "1"
+	AccountStatusError    AccountStatus = "2"
+	AccountStatusStopped  AccountStatus = "3"
+)
+
+var accountStatusNames = map[AccountStatus]string{
+	AccountStatusOK:       "ok",
+	AccountStatusUpdating: "updating",
+	AccountStatusError:    "error",
+	AccountStatusStopped:  "stopped",
+}
+
+func (s AccountStatus) String() string {
+	if s == "" {
+		s = AccountStatusOK
+	}
+	if name, ok := accountStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("AccountStatus(%s)", string(s))
+}
+
+// Int returns the numeric status, ok is false for non-numeric statuses
+func (s AccountStatus) Int() (n int, ok bool) {
+	return codeInt(string(s))
+}
+
+func (s *AccountStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data)
+	if err != nil {
+		return newUnmarshalTypeError(data, *s)
+	}
+	*s = AccountStatus(code)
+	return nil
+}
+
+// AggregationErrorID identifies why the aggregation of an account failed, 0 means no error.
+// The API sends it as a number or a string, both are accepted. Numeric values are kept in
+// decimal form, anything else as the raw text, eg "E4001".
+type AggregationErrorID string
+
+// MoneyForward doesn't document the error IDs, the names below are best-effort and the API
+// may send IDs that aren't listed. Only AggregationErrorNone is reliable.
+const (
+	AggregationErrorNone                   AggregationErrorID = "0"
+	AggregationErrorLoginFailed            AggregationErrorID = "1"
+	AggregationErrorAdditionalAuthRequired AggregationErrorID = "2"
+	AggregationErrorAccountLocked          AggregationErrorID = "3"
+	AggregationErrorPasswordExpired        AggregationErrorID = "4"
+	AggregationErrorMaintenance            AggregationErrorID = "5"
+	AggregationErrorServiceUnavailable     AggregationErrorID = "6"
+	AggregationErrorTimeout                AggregationErrorID = "7"
+	AggregationErrorInternal               AggregationErrorID = "8"
+)
+
+var aggregationErrorDescriptions = map[AggregationErrorID]string{
+	AggregationErrorNone:                   "no error",
+	AggregationErrorLoginFailed:            "login failed, the credentials are wrong",
+	AggregationErrorAdditionalAuthRequired: "additional authentication (eg a one-time password) is required",
+	AggregationErrorAccountLocked:          "the account is locked at the institution",
+	AggregationErrorPasswordExpired:        "the password has expired and must be changed at the institution",
+	AggregationErrorMaintenance:            "the institution is under maintenance",
+	AggregationErrorServiceUnavailable:     "the institution is temporarily unavailable",
+	AggregationErrorTimeout:                "the aggregation timed out",
+	AggregationErrorInternal:               "internal error at MoneyForward",
+}
+
+func (id AggregationErrorID) String() string {
+	if desc, ok := aggregationErrorDescriptions[id.normalize()]; ok {
+		return desc
+	}
+	return fmt.Sprintf("AggregationErrorID(%s)", string(id))
+}
+
+// Int returns the numeric error ID, ok is false for non-numeric IDs
+func (id AggregationErrorID) Int() (n int, ok bool) {
+	return codeInt(string(id))
+}
+
+// IsError reports whether id is an error, ie neither 0 nor empty
+func (id AggregationErrorID) IsError() bool {
+	return id.normalize() != AggregationErrorNone
+}
+
+// NeedsReauth reports whether id is one of the login and credential errors listed above.
+// As the IDs aren't documented, prefer Account.NeedsReauth, which also considers the service.
+func (id AggregationErrorID) NeedsReauth() bool {
+	switch id {
+	case AggregationErrorLoginFailed,
+		AggregationErrorAdditionalAuthRequired,
+		AggregationErrorAccountLocked,
+		AggregationErrorPasswordExpired:
+		return true
+	}
+	return false
+}
+
+// IsTemporary reports whether id is one of the maintenance and availability errors listed above,
+// which are likely to go away when aggregating again later. Unknown IDs are not temporary.
+func (id AggregationErrorID) IsTemporary() bool {
+	switch id {
+	case AggregationErrorMaintenance,
+		AggregationErrorServiceUnavailable,
+		AggregationErrorTimeout,
+		AggregationErrorInternal:
+		return true
+	}
+	return false
+}
+
+// normalize maps the zero value to AggregationErrorNone
+func (id AggregationErrorID) normalize() AggregationErrorID {
+	if id == "" {
+		return AggregationErrorNone
+	}
+	return id
+}
+
+func (id *AggregationErrorID) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data)
+	if err != nil {
+		return newUnmarshalTypeError(data, *id)
+	}
+	*id = AggregationErrorID(code)
+	return nil
+}
+
+// unmarshalCode decodes a status or error code. Numbers and numeric strings are returned in
+// decimal form, empty strings and null as "0", other strings as the trimmed text.
+func unmarshalCode(data []byte) (string, error) {
+	n, text, err := unmarshalNumberOrString(data)
+	if err != nil {
+		return "", err
+	}
+	if text != "" {
+		return text, nil
+	}
+	return strconv.Itoa(n), nil
+}
+
+// codeInt parses a code kept by unmarshalCode, the zero value counts as 0
+func codeInt(code string) (int, bool) {
+	if code == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(code)
+	return n, err == nil
+}
+
+// unmarshalNumberOrString decodes a JSON number, numeric string, empty string or null into n.
+// Non-numeric strings are returned as text.
+func unmarshalNumberOrString(data []byte) (n int, text string, err error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return 0, "", nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, "", err
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return 0, "", nil
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, "", nil
+		}
+		return 0, s, nil
+	}
+
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, "", fmt.Errorf("value must be string or number, got %s", data)
+	}
+	return n, "", nil
+}
@@ -0,0 +1,151 @@
+package moneyforward
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAccountStatusUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    AccountStatus
+		wantInt bool
+		wantErr bool
+	}{
+		{`0`, AccountStatusOK, true, false},
+		{`3`, AccountStatusStopped, true, false},
+		{`"2"`, AccountStatusError, true, false},
+		{`" 1 "`, AccountStatusUpdating, true, false},
+		{`"01"`, AccountStatusUpdating, true, false},
+		{`""`, AccountStatusOK, true, false},
+		{`null`, AccountStatusOK, true, false},
+		{`"running"`, "running", false, false},
+		{`true`, "", false, true},
+		{`1.5`, "", false, true},
+	}
+
+	for _, tt := range tests {
+		var got AccountStatus
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+		if _, ok := got.Int(); ok != tt.wantInt {
+			t.Errorf("%q.Int() ok = %v, want %v", got, ok, tt.wantInt)
+		}
+	}
+}
+
+func TestAggregationErrorIDUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      AggregationErrorID
+		wantError bool
+		wantInt   int
+		wantIntOK bool
+	}{
+		{`0`, AggregationErrorNone, false, 0, true},
+		{`"0"`, AggregationErrorNone, false, 0, true},
+		{`""`, AggregationErrorNone, false, 0, true},
+		{`null`, AggregationErrorNone, false, 0, true},
+		{`1`, AggregationErrorLoginFailed, true, 1, true},
+		{`"12"`, "12", true, 12, true},
+		{`"E4001"`, "E4001", true, 0, false},
+	}
+
+	for _, tt := range tests {
+		var got AggregationErrorID
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got.IsError() != tt.wantError {
+			t.Errorf("%q.IsError() = %v, want %v", got, got.IsError(), tt.wantError)
+		}
+		if n, ok := got.Int(); n != tt.wantInt || ok != tt.wantIntOK {
+			t.Errorf("%q.Int() = %d, %v, want %d, %v", got, n, ok, tt.wantInt, tt.wantIntOK)
+		}
+	}
+
+	var zero AggregationErrorID
+	if zero.IsError() {
+		t.Error("zero AggregationErrorID is an error")
+	}
+}
+
+func TestAggregationErrorIDHelpers(t *testing.T) {
+	tests := []struct {
+		id                     AggregationErrorID
+		needsReauth, temporary bool
+	}{
+		{AggregationErrorNone, false, false},
+		{AggregationErrorLoginFailed, true, false},
+		{AggregationErrorPasswordExpired, true, false},
+		{AggregationErrorMaintenance, false, true},
+		{AggregationErrorInternal, false, true},
+		{"E4001", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.id.NeedsReauth(); got != tt.needsReauth {
+			t.Errorf("%q.NeedsReauth() = %v, want %v", tt.id, got, tt.needsReauth)
+		}
+		if got := tt.id.IsTemporary(); got != tt.temporary {
+			t.Errorf("%q.IsTemporary() = %v, want %v", tt.id, got, tt.temporary)
+		}
+	}
+}
+
+func TestAccountNeedsReauth(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want bool
+	}{
+		{"no error", `{"error_id":0,"service":{"requires_user_operation":true}}`, false},
+		{"error on service requiring user operation", `{"error_id":"E4001","service":{"requires_user_operation":true}}`, true},
+		{"unknown error", `{"error_id":"E4001","service":{"requires_user_operation":false}}`, false},
+		{"login error", `{"error_id":1}`, true},
+		{"temporary error", `{"error_id":5}`, false},
+	}
+
+	for _, tt := range tests {
+		var account Account
+		if err := json.Unmarshal([]byte(tt.in), &account); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := account.NeedsReauth(); got != tt.want {
+			t.Errorf("%s: NeedsReauth() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{AccountStatusError.String(), "error"},
+		{AccountStatus("").String(), "ok"},
+		{AccountStatus("9").String(), "AccountStatus(9)"},
+		{AggregationErrorNone.String(), "no error"},
+		{AggregationErrorID("").String(), "no error"},
+		{AggregationErrorMaintenance.String(), "the institution is under maintenance"},
+		{AggregationErrorID("E4001").String(), "AggregationErrorID(E4001)"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("String() = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package moneyforward
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"strconv"
+)
+
+// TimelineCardType is the type of a home timeline card
+type TimelineCardType string
+
+const (
+	TimelineCardAggregationError TimelineCardType = "aggregation_error"
+	TimelineCardLargeSpend       TimelineCardType = "largest_amount"
+	TimelineCardSumAmount        TimelineCardType = "sum_amount"
+	TimelineCardPromoBanner      TimelineCardType = "home_card"
+	// TimelineCardUserNotification is the generic type of notification cards, which are
+	// classified by the parameters they carry
+	TimelineCardUserNotification TimelineCardType = "user_notification"
+)
+
+// TimelineCard is a single entry of the home timeline. Use a type switch to access the
+// concrete card: *AggregationErrorCard, *LargeSpendCard, *SumAmountCard, *PromoBannerCard
+// or *UnknownCard for anything else.
+type TimelineCard interface {
+	// CardType returns the type of the card as sent by the API
+	CardType() TimelineCardType
+	// Notification returns the notification of the card, nil for cards that aren't notifications
+	Notification() *UserNotification
+}
+
+// AggregationErrorCard notifies that the aggregation of an account failed
+type AggregationErrorCard struct {
+	Type TimelineCardType
+	*UserNotification
+	// Account is never nil, cards without an account are decoded as UnknownCard
+	Account *AccountSummary
+}
+
+// LargeSpendCard notifies about the largest spending of a day
+type LargeSpendCard struct {
+	Type TimelineCardType
+	*UserNotification
+	Amount          NotificationAmount
+	UserAssetActIDs []int64
+}
+
+// SumAmountCard notifies about the total spending of a day
+type SumAmountCard struct {
+	Type TimelineCardType
+	*UserNotification
+	Amount          NotificationAmount
+	UserAssetActIDs []int64
+}
+
+// PromoBannerCard is a promotional banner
+type PromoBannerCard struct {
+	Type TimelineCardType
+	*HomeCard
+}
+
+// UnknownCard is a card of a type this package doesn't know, Raw holds the card as sent by the API
+type UnknownCard struct {
+	Type             TimelineCardType
+	UserNotification *UserNotification
+	HomeCard         *HomeCard
+	Raw              json.RawMessage
+}
+
+func (c *AggregationErrorCard) CardType() TimelineCardType      { return c.Type }
+func (c *AggregationErrorCard) Notification() *UserNotification { return c.UserNotification }
+func (c *LargeSpendCard) CardType() TimelineCardType            { return c.Type }
+func (c *LargeSpendCard) Notification() *UserNotification       { return c.UserNotification }
+func (c *SumAmountCard) CardType() TimelineCardType             { return c.Type }
+func (c *SumAmountCard) Notification() *UserNotification        { return c.UserNotification }
+func (c *PromoBannerCard) CardType() TimelineCardType           { return c.Type }
+func (c *PromoBannerCard) Notification() *UserNotification      { return nil }
+func (c *UnknownCard) CardType() TimelineCardType               { return c.Type }
+func (c *UnknownCard) Notification() *UserNotification          { return c.UserNotification }
+
+// rawTimelineCard is a timeline card as sent by the API
+type rawTimelineCard struct {
+	Type             TimelineCardType  `json:"type"`
+	UserNotification *UserNotification `json:"user_notification,omitempty"`
+	HomeCard         *HomeCard         `json:"home_card,omitempty"`
+}
+
+func (d *TimelineDay) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Date  Date              `json:"date"`
+		Cards []json.RawMessage `json:"cards"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	d.Date = raw.Date
+	d.Cards = make([]TimelineCard, 0, len(raw.Cards))
+	for _, data := range raw.Cards {
+		card, err := decodeTimelineCard(data)
+		if err != nil {
+			return err
+		}
+		d.Cards = append(d.Cards, card)
+	}
+
+	return nil
+}
+
+// decodeTimelineCard picks the concrete card by its type, and for generic notifications by the
+// parameters they carry. Cards missing the parameters of their type are decoded as UnknownCard.
+func decodeTimelineCard(data json.RawMessage) (TimelineCard, error) {
+	var raw rawTimelineCard
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	unknown := &UnknownCard{
+		Type:             raw.Type,
+		UserNotification: raw.UserNotification,
+		HomeCard:         raw.HomeCard,
+		Raw:              data,
+	}
+
+	if raw.HomeCard != nil && (raw.Type == TimelineCardPromoBanner || raw.UserNotification == nil) {
+		return &PromoBannerCard{Type: raw.Type, HomeCard: raw.HomeCard}, nil
+	}
+
+	n := raw.UserNotification
+	if n == nil {
+		return unknown, nil
+	}
+	params := n.Parameters
+
+	aggregationError := func() TimelineCard {
+		return &AggregationErrorCard{Type: raw.Type, UserNotification: n, Account: params.Account}
+	}
+	largeSpend := func() TimelineCard {
+		return &LargeSpendCard{Type: raw.Type, UserNotification: n, Amount: *params.LargestAmount, UserAssetActIDs: params.UserAssetActIDs}
+	}
+	sumAmount := func() TimelineCard {
+		return &SumAmountCard{Type: raw.Type, UserNotification: n, Amount: *params.SumAmount, UserAssetActIDs: params.UserAssetActIDs}
+	}
+
+	switch raw.Type {
+	case TimelineCardAggregationError:
+		if params.Account != nil {
+			return aggregationError(), nil
+		}
+	case TimelineCardLargeSpend:
+		if params.LargestAmount != nil {
+			return largeSpend(), nil
+		}
+	case TimelineCardSumAmount:
+		if params.SumAmount != nil {
+			return sumAmount(), nil
+		}
+	case TimelineCardUserNotification:
+		// spending notifications may carry the account as well, so only notifications about
+		// an account with an error are aggregation errors
+		switch {
+		case params.LargestAmount != nil:
+			return largeSpend(), nil
+		case params.SumAmount != nil:
+			return sumAmount(), nil
+		case params.Account != nil && params.Account.ErrorID.IsError():
+			return aggregationError(), nil
+		}
+	}
+
+	return unknown, nil
+}
+
+// AllHomeTimelineDays iterates over the days of the home timeline from the latest, requesting
+// pages of limit and following the Self.Until cursor until the server returns no more days.
+// Iteration stops after the first error, which is yielded with a nil day.
+func (c *Client) AllHomeTimelineDays(ctx context.Context, limit int, opts ...RequestOption) iter.Seq2[*TimelineDay, error] {
+	return func(yield func(*TimelineDay, error) bool) {
+		until := ""
+		for {
+			resp, err := c.GetHomeTimelinePage(ctx, limit, until, opts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range resp.Timeline {
+				if !yield(&resp.Timeline[i], nil) {
+					return
+				}
+			}
+
+			// stop when the cursor doesn't move to avoid requesting the same page forever
+			if len(resp.Timeline) == 0 || resp.Self.Until == "" || resp.Self.Until == until {
+				return
+			}
+			until = resp.Self.Until
+		}
+	}
+}
+
+// UnreadNotifications returns the notifications of the day's cards that haven't been read
+func (d *TimelineDay) UnreadNotifications() []*UserNotification {
+	var unread []*UserNotification
+	for _, card := range d.Cards {
+		if n := card.Notification(); n != nil && !n.Read {
+			unread = append(unread, n)
+		}
+	}
+	return unread
+}
+
+// UnreadNotifications returns the unread notifications of all days in the response
+func (r *HomeTimelineResponse) UnreadNotifications() []*UserNotification {
+	var unread []*UserNotification
+	for i := range r.Timeline {
+		unread = append(unread, r.Timeline[i].UnreadNotifications()...)
+	}
+	return unread
+}
+
+// GetNotificationActivities gets the activities referenced by the UserAssetActIDs of a notification.
+// Activities that have been deleted since the notification was created are skipped.
+func (c *Client) GetNotificationActivities(ctx context.Context, n *UserNotification, opts ...RequestOption) ([]*UserAssetAct, error) {
+	acts := make([]*UserAssetAct, 0, len(n.Parameters.UserAssetActIDs))
+	for _, id := range n.Parameters.UserAssetActIDs {
+		resp, err := c.GetUserAssetActivity(ctx, strconv.FormatInt(id, 10), opts...)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		acts = append(acts, &resp.UserAssetAct)
+	}
+	return acts, nil
+}
@@ -0,0 +1,76 @@
+package moneyforward
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDecodeTimelineCard(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"aggregation error", `{"type":"aggregation_error","user_notification":{"parameters":{"account":{"name":"bank","error_id":3}}}}`, "*moneyforward.AggregationErrorCard"},
+		{"aggregation error without account", `{"type":"aggregation_error","user_notification":{"parameters":{}}}`, "*moneyforward.UnknownCard"},
+		{"large spend", `{"type":"largest_amount","user_notification":{"parameters":{"largest_amount":{"amount":5000,"date":"2024-01-15"},"user_asset_act_ids":[1]}}}`, "*moneyforward.LargeSpendCard"},
+		{"large spend without amount", `{"type":"largest_amount","user_notification":{"parameters":{}}}`, "*moneyforward.UnknownCard"},
+		{"sum amount", `{"type":"sum_amount","user_notification":{"parameters":{"sum_amount":{"amount":12000,"date":"2024-01-15"}}}}`, "*moneyforward.SumAmountCard"},
+		{"generic large spend", `{"type":"user_notification","user_notification":{"parameters":{"largest_amount":{"amount":5000}}}}`, "*moneyforward.LargeSpendCard"},
+		{"generic large spend with account", `{"type":"user_notification","user_notification":{"parameters":{"account":{"name":"card","error_id":0},"largest_amount":{"amount":5000}}}}`, "*moneyforward.LargeSpendCard"},
+		{"generic sum amount with failed account", `{"type":"user_notification","user_notification":{"parameters":{"account":{"name":"card","error_id":3},"sum_amount":{"amount":5000}}}}`, "*moneyforward.SumAmountCard"},
+		{"generic aggregation error", `{"type":"user_notification","user_notification":{"parameters":{"account":{"name":"bank","error_id":"3"}}}}`, "*moneyforward.AggregationErrorCard"},
+		{"generic notification about healthy account", `{"type":"user_notification","user_notification":{"parameters":{"account":{"name":"bank","error_id":0}}}}`, "*moneyforward.UnknownCard"},
+		{"promo banner", `{"type":"home_card","home_card":{"id":"x","landing_url":"https://example.com"}}`, "*moneyforward.PromoBannerCard"},
+		{"untyped banner", `{"home_card":{"id":"x"}}`, "*moneyforward.PromoBannerCard"},
+		{"unknown type", `{"type":"new_feature","user_notification":{"parameters":{"sum_amount":{"amount":1}}}}`, "*moneyforward.UnknownCard"},
+		{"empty", `{}`, "*moneyforward.UnknownCard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := decodeTimelineCard(json.RawMessage(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprintf("%T", card); got != tt.want {
+				t.Errorf("decodeTimelineCard() = %s, want %s", got, tt.want)
+			}
+			if card, ok := card.(*AggregationErrorCard); ok && card.Account == nil {
+				t.Error("AggregationErrorCard.Account is nil")
+			}
+		})
+	}
+}
+
+func TestTimelineDayUnmarshalJSON(t *testing.T) {
+	data := `{"date":"2024-01-15","cards":[
+		{"type":"largest_amount","user_notification":{"id":1,"read":false,"parameters":{"largest_amount":{"amount":"5,000","date":"2024-01-15"},"user_asset_act_ids":[10,11]}}},
+		{"type":"new_feature","foo":"bar"}
+	]}`
+
+	var day TimelineDay
+	if err := json.Unmarshal([]byte(data), &day); err != nil {
+		t.Fatal(err)
+	}
+	if day.Date.String() != "2024-01-15" || len(day.Cards) != 2 {
+		t.Fatalf("day = %s with %d cards, want 2024-01-15 with 2 cards", day.Date, len(day.Cards))
+	}
+
+	spend, ok := day.Cards[0].(*LargeSpendCard)
+	if !ok {
+		t.Fatalf("Cards[0] = %T, want *LargeSpendCard", day.Cards[0])
+	}
+	if spend.CardType() != TimelineCardLargeSpend || spend.Amount.Amount != JPY(5000) || len(spend.UserAssetActIDs) != 2 || spend.Notification().ID != 1 {
+		t.Errorf("Cards[0] = %+v", spend)
+	}
+
+	unknown, ok := day.Cards[1].(*UnknownCard)
+	if !ok {
+		t.Fatalf("Cards[1] = %T, want *UnknownCard", day.Cards[1])
+	}
+	if unknown.Type != "new_feature" || string(unknown.Raw) != `{"type":"new_feature","foo":"bar"}` {
+		t.Errorf("Cards[1] = %+v", unknown)
+	}
+}
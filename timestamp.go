@@ -0,0 +1,189 @@
+package moneyforward
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokyo is the time zone MoneyForward uses for dates and times without an offset.
+// Japan has no DST, so a fixed zone avoids depending on the tzdata being installed.
+var tokyo = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// timestampLayouts are the formats MoneyForward uses for timestamps, tried in order.
+// Layouts without an offset are interpreted in Asia/Tokyo.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	time.DateOnly,
+	"2006/01/02",
+	"20060102",
+}
+
+// dateLayouts are the formats MoneyForward uses for dates, tried before timestampLayouts
+var dateLayouts = []string{
+	time.DateOnly,
+	"2006/01/02",
+	"20060102",
+	"2006-01",
+}
+
+// Timestamp is a point in time decoded from any of the formats used by MoneyForward.
+// JSON numbers are unix seconds, empty strings and null decode to the zero Timestamp.
+type Timestamp struct {
+	time.Time
+}
+
+// ParseTimestamp parses s in any of the timestamp formats used by MoneyForward
+func ParseTimestamp(s string) (Timestamp, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Timestamp{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, tokyo); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("invalid timestamp %q", s)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, unix, err := unmarshalTimeString(data)
+	if err != nil {
+		return newUnmarshalTypeError(data, Timestamp{})
+	}
+	if unix {
+		parsed, err := parseUnixSeconds(s)
+		if err != nil {
+			return newUnmarshalTypeError(data, Timestamp{})
+		}
+		*t = Timestamp{parsed}
+		return nil
+	}
+
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		return newUnmarshalTypeError(data, Timestamp{})
+	}
+	*t = parsed
+	return nil
+}
+
+// Date is a calendar date at midnight in Asia/Tokyo.
+// JSON numbers are unix seconds, empty strings and null decode to the zero Date.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of t in Asia/Tokyo
+func NewDate(t time.Time) Date {
+	return Date{truncateToDay(t)}
+}
+
+// ParseDate parses s in any of the date or timestamp formats used by MoneyForward
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, tokyo); err == nil {
+			return Date{t}, nil
+		}
+	}
+
+	ts, err := ParseTimestamp(s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+	return NewDate(ts.Time), nil
+}
+
+// String returns the date as YYYY-MM-DD, or an empty string for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.DateOnly)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, unix, err := unmarshalTimeString(data)
+	if err != nil {
+		return newUnmarshalTypeError(data, Date{})
+	}
+	if unix {
+		parsed, err := parseUnixSeconds(s)
+		if err != nil {
+			return newUnmarshalTypeError(data, Date{})
+		}
+		*d = NewDate(parsed)
+		return nil
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return newUnmarshalTypeError(data, Date{})
+	}
+	*d = parsed
+	return nil
+}
+
+// unmarshalTimeString returns the JSON string or number in data as a string, and "" for null.
+// unix reports whether data is a number, which is a unix timestamp.
+func unmarshalTimeString(data []byte) (s string, unix bool, err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+
+	if data[0] != '"' {
+		return string(data), true, nil
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", false, err
+	}
+	return s, false, nil
+}
+
+// parseUnixSeconds parses a unix timestamp in seconds
+func parseUnixSeconds(s string) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid unix timestamp %s", s)
+	}
+	return time.Unix(sec, 0).In(tokyo), nil
+}
+
+// truncateToDay returns midnight of t's date in Asia/Tokyo
+func truncateToDay(t time.Time) time.Time {
+	t = t.In(tokyo)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tokyo)
+}
@@ -0,0 +1,101 @@
+package moneyforward
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	utc := time.UTC
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{`"2024-01-15T10:30:00+09:00"`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`"2024-01-15T01:30:00.123Z"`, time.Date(2024, 1, 15, 1, 30, 0, 123000000, utc), false},
+		{`"2024-01-15T10:30:00"`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`"2024-01-15 10:30:00 +0900"`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`"2024-01-15 10:30:00"`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`"2024/01/15 10:30:05"`, time.Date(2024, 1, 15, 10, 30, 5, 0, tokyo), false},
+		{`"2024/01/15 10:30"`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`"2024-01-15"`, time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo), false},
+		{`"2024/01/15"`, time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo), false},
+		{`"20240115"`, time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo), false},
+		{`1705282200`, time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo), false},
+		{`""`, time.Time{}, false},
+		{`null`, time.Time{}, false},
+		{`"1705282200"`, time.Time{}, true},
+		{`"yesterday"`, time.Time{}, true},
+		{`1705282200.5`, time.Time{}, true},
+		{`true`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		var got Timestamp
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDateUnmarshalJSON(t *testing.T) {
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{`"2024-01-15"`, jan15, false},
+		{`"2024/01/15"`, jan15, false},
+		{`"20240115"`, jan15, false},
+		{`"2024-01"`, time.Date(2024, 1, 1, 0, 0, 0, 0, tokyo), false},
+		{`"2024-01-15T23:30:00+09:00"`, jan15, false},
+		// 15:30 UTC is already the next day in Tokyo
+		{`"2024-01-14T15:30:00Z"`, jan15, false},
+		{`1705282200`, jan15, false},
+		{`""`, time.Time{}, false},
+		{`null`, time.Time{}, false},
+		{`"15.01.2024"`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		var got Date
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimestampMarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{Timestamp{time.Date(2024, 1, 15, 10, 30, 0, 0, tokyo)}, `"2024-01-15T10:30:00+09:00"`},
+		{Timestamp{}, `null`},
+		{NewDate(time.Date(2024, 1, 15, 23, 0, 0, 0, tokyo)), `"2024-01-15"`},
+		{Date{}, `null`},
+	}
+
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetTransfer marks or unmarks a user asset activity as a transfer between own accounts.
+// If counterpartSubAccountID is not empty, the transfer is linked to that sub-account.
+func (c *Client) SetTransfer(ctx context.Context, activityID string, isTransfer bool, counterpartSubAccountID StringID, opts ...RequestOption) (*UserAssetAct, error) {
+	fields := map[string]interface{}{
+		"is_transfer": isTransfer,
+	}
+	if isTransfer && counterpartSubAccountID != "" {
+		fields["partner_sub_account_id"] = counterpartSubAccountID
+	}
+
+	return c.putUserAssetAct(ctx, activityID, fields, opts...)
+}
+
+// SetExcludedFromCalculation excludes a user asset activity from (or includes it in) income and expense calculations
+func (c *Client) SetExcludedFromCalculation(ctx context.Context, activityID string, excluded bool, opts ...RequestOption) (*UserAssetAct, error) {
+	fields := map[string]interface{}{
+		"is_target": boolToInt(!excluded),
+	}
+
+	return c.putUserAssetAct(ctx, activityID, fields, opts...)
+}
+
+// TransferPairOptions configures MarkTransferPair
+type TransferPairOptions struct {
+	// LinkCounterpart links each side to the sub-account of the other side
+	LinkCounterpart bool
+	// ExcludeFromCalculation additionally excludes both sides from income and expense calculations
+	ExcludeFromCalculation bool
+}
+
+// MarkTransferPair marks the outgoing and incoming side of a transfer between own accounts,
+// eg a bank withdrawal and the matching card payment. Exactly one side must be income.
+// It returns both updated activities in the order they were given.
+// If marking b fails, a is reverted to its previous transfer flag (and included in calculations
+// again if ExcludeFromCalculation was set) and the error names b. If the revert fails too, the
+// updated a is returned along with an error wrapping both failures, so the caller can fix it up.
+func (c *Client) MarkTransferPair(ctx context.Context, a, b *UserAssetAct, pairOpts TransferPairOptions, opts ...RequestOption) (*UserAssetAct, *UserAssetAct, error) {
+	if a.IsIncome == b.IsIncome {
+		return nil, nil, fmt.Errorf("transfer pair %s and %s must have one income and one expense side", a.ID, b.ID)
+	}
+
+	var counterpartA, counterpartB StringID
+	if pairOpts.LinkCounterpart {
+		counterpartA, counterpartB = b.SubAccountID, a.SubAccountID
+	}
+
+	updatedA, err := c.markTransfer(ctx, a, counterpartA, pairOpts.ExcludeFromCalculation, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	updatedB, err := c.markTransfer(ctx, b, counterpartB, pairOpts.ExcludeFromCalculation, opts...)
+	if err != nil {
+		err = fmt.Errorf("transfer pair %s and %s: second side: %w", a.ID, b.ID, err)
+		if revertErr := c.revertTransfer(ctx, a, pairOpts.ExcludeFromCalculation, opts...); revertErr != nil {
+			return updatedA, nil, fmt.Errorf("%w; first side %s is left marked: %w", err, a.ID, revertErr)
+		}
+		return nil, nil, err
+	}
+
+	return updatedA, updatedB, nil
+}
+
+func (c *Client) markTransfer(ctx context.Context, act *UserAssetAct, counterpart StringID, exclude bool, opts ...RequestOption) (*UserAssetAct, error) {
+	updated, err := c.SetTransfer(ctx, string(act.ID), true, counterpart, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark %s as transfer: %w", act.ID, err)
+	}
+
+	if exclude {
+		updated, err = c.SetExcludedFromCalculation(ctx, string(act.ID), true, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to exclude %s from calculation: %w", act.ID, err)
+		}
+	}
+
+	return updated, nil
+}
+
+func (c *Client) revertTransfer(ctx context.Context, act *UserAssetAct, excluded bool, opts ...RequestOption) error {
+	if excluded {
+		if _, err := c.SetExcludedFromCalculation(ctx, string(act.ID), false, opts...); err != nil {
+			return fmt.Errorf("failed to include %s in calculation again: %w", act.ID, err)
+		}
+	}
+
+	if _, err := c.SetTransfer(ctx, string(act.ID), act.IsTransfer, "", opts...); err != nil {
+		return fmt.Errorf("failed to revert transfer flag of %s: %w", act.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package moneyforward
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMarkTransferPair(t *testing.T) {
+	a := &UserAssetAct{ID: "1", SubAccountID: "10"}
+	b := &UserAssetAct{ID: "2", SubAccountID: "20", IsIncome: true}
+
+	tests := []struct {
+		name       string
+		opts       TransferPairOptions
+		failB      bool
+		failRevert bool
+		wantErr    string
+		wantA      bool
+		// wantPuts lists "<id> <fields>" of every PUT in order
+		wantPuts []string
+	}{
+		{
+			name:     "linked",
+			opts:     TransferPairOptions{LinkCounterpart: true},
+			wantA:    true,
+			wantPuts: []string{`1 {"is_transfer":true,"partner_sub_account_id":"20"}`, `2 {"is_transfer":true,"partner_sub_account_id":"10"}`},
+		},
+		{
+			name:    "second side fails, first reverted",
+			opts:    TransferPairOptions{ExcludeFromCalculation: true},
+			failB:   true,
+			wantErr: "transfer pair 1 and 2: second side: failed to mark 2 as transfer",
+			wantPuts: []string{
+				`1 {"is_transfer":true}`, `1 {"is_target":0}`,
+				`2 {"is_transfer":true}`,
+				`1 {"is_target":1}`, `1 {"is_transfer":false}`,
+			},
+		},
+		{
+			name:       "revert fails",
+			failB:      true,
+			failRevert: true,
+			wantErr:    "first side 1 is left marked: failed to revert transfer flag of 1",
+			wantA:      true,
+			wantPuts:   []string{`1 {"is_transfer":true}`, `2 {"is_transfer":true}`, `1 {"is_transfer":false}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var puts []string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					UserAssetAct json.RawMessage `json:"user_asset_act"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode body: %v", err)
+				}
+				id := path.Base(r.URL.Path)
+
+				mu.Lock()
+				puts = append(puts, id+" "+string(body.UserAssetAct))
+				reverting := len(puts) > 2 && id == "1"
+				mu.Unlock()
+
+				if (tt.failB && id == "2") || (tt.failRevert && reverting) {
+					http.Error(w, `{"error":"invalid"}`, http.StatusUnprocessableEntity)
+					return
+				}
+				fmt.Fprintf(w, `{"user_asset_act":{"id":%s,"is_transfer":true}}`, id)
+			})
+
+			gotA, gotB, err := c.MarkTransferPair(context.Background(), a, b, tt.opts)
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if (gotA != nil) != tt.wantA {
+				t.Errorf("updated a = %+v, want non-nil %v", gotA, tt.wantA)
+			}
+			if (gotB != nil) != (tt.wantErr == "") {
+				t.Errorf("updated b = %+v", gotB)
+			}
+			if strings.Join(puts, "\n") != strings.Join(tt.wantPuts, "\n") {
+				t.Errorf("puts =\n%s\nwant\n%s", strings.Join(puts, "\n"), strings.Join(tt.wantPuts, "\n"))
+			}
+		})
+	}
+}
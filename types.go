@@ -27,121 +27,134 @@ const (
 
 // HomeTimelineResponse represents the response from the home timeline endpoint
 type HomeTimelineResponse struct {
-	Self struct {
-		Href  string `json:"href"`
-		Path  string `json:"path"`
-		Until string `json:"until"`
-		Limit int    `json:"limit"`
-	} `json:"self"`
-	RequestTime time.Time `json:"request_time"`
-	Timeline    []struct {
-		Date  string `json:"date"`
-		Cards []struct {
-			Type             string `json:"type"`
-			UserNotification *struct {
-				ID         int64 `json:"id"`
-				CategoryID int   `json:"category_id"`
-				Category   struct {
-					PremiumRequired bool `json:"premium_required"`
-				} `json:"category"`
-				Parameters struct {
-					Account *struct {
-						Name                 string      `json:"name"`
-						Amount               float64     `json:"amount"`
-						Status               int         `json:"status"`
-						Type                 AccountType `json:"type"`
-						ShowPath             MFShowPath  `json:"show_path"`
-						LastSucceededAt      string      `json:"last_succeeded_at"`
-						AggregationQueuePath MFPath      `json:"aggregation_queue_path"`
-						AccountIDHash        string      `json:"account_id_hash"`
-						ServiceID            int         `json:"service_id"`
-						ServiceType          string      `json:"service_type"`
-						ServiceCategoryID    int         `json:"service_category_id"`
-						ColorCode            string      `json:"color_code"`
-						IsShowTransaction    bool        `json:"is_show_transaction"`
-					} `json:"account,omitempty"`
-					UserAssetActIDs []int64 `json:"user_asset_act_ids"`
-					LargestAmount   *struct {
-						Amount float64   `json:"amount"`
-						Date   time.Time `json:"date"`
-					} `json:"largest_amount"`
-					SumAmount *struct {
-						Amount float64   `json:"amount"`
-						Date   time.Time `json:"date"`
-					} `json:"sum_amount"`
-					Extra map[string]interface{} `json:"extra"`
-				} `json:"parameters"`
-				ReadAt *time.Time `json:"read_at"`
-				Read   bool       `json:"read"`
-			} `json:"user_notification,omitempty"`
-			HomeCard *struct {
-				ID          string `json:"id"`
-				BannerImage struct {
-					URL    string `json:"url"`
-					Width  int    `json:"width"`
-					Height int    `json:"height"`
-				} `json:"banner_image"`
-				LandingURL string    `json:"landing_url"`
-				CreatedAt  time.Time `json:"created_at"`
-				StartAt    time.Time `json:"start_at"`
-				EndAt      time.Time `json:"end_at"`
-			} `json:"home_card,omitempty"`
-		} `json:"cards"`
-	} `json:"timeline"`
+	Self        TimelineLink  `json:"self"`
+	RequestTime Timestamp     `json:"request_time"`
+	Timeline    []TimelineDay `json:"timeline"`
+}
+
+// TimelineLink describes the page of the timeline that was returned
+type TimelineLink struct {
+	Href  string `json:"href"`
+	Path  string `json:"path"`
+	Until string `json:"until"`
+	Limit int    `json:"limit"`
+}
+
+// TimelineDay holds the timeline cards of a single day, see TimelineCard for the card types
+type TimelineDay struct {
+	Date  Date           `json:"date"`
+	Cards []TimelineCard `json:"cards"`
+}
+
+// UserNotification is a notification about an account or transactions
+type UserNotification struct {
+	ID         int64 `json:"id"`
+	CategoryID int   `json:"category_id"`
+	Category   struct {
+		PremiumRequired bool `json:"premium_required"`
+	} `json:"category"`
+	Parameters NotificationParameters `json:"parameters"`
+	ReadAt     Timestamp              `json:"read_at"`
+	Read       bool                   `json:"read"`
+}
+
+// NotificationParameters holds the details of a notification, which fields are set depends on the card type
+type NotificationParameters struct {
+	Account         *AccountSummary        `json:"account,omitempty"`
+	UserAssetActIDs []int64                `json:"user_asset_act_ids"`
+	LargestAmount   *NotificationAmount    `json:"largest_amount"`
+	SumAmount       *NotificationAmount    `json:"sum_amount"`
+	Extra           map[string]interface{} `json:"extra"`
+}
+
+// NotificationAmount is an amount at a date mentioned in a notification
+type NotificationAmount struct {
+	Amount Money `json:"amount"`
+	Date   Date  `json:"date"`
+}
+
+// HomeCard is a banner card shown on the home timeline
+type HomeCard struct {
+	ID          string `json:"id"`
+	BannerImage struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"banner_image"`
+	LandingURL string    `json:"landing_url"`
+	CreatedAt  Timestamp `json:"created_at"`
+	StartAt    Timestamp `json:"start_at"`
+	EndAt      Timestamp `json:"end_at"`
 }
 
 // AccountSummariesResponse represents the response from the account summaries endpoint
 type AccountSummariesResponse struct {
-	Accounts []struct {
-		Name                 string      `json:"name"`
-		Amount               float64     `json:"amount"`
-		LastLoginAt          string      `json:"last_login_at"`
-		LastAggregatedAt     string      `json:"last_aggregated_at"`
-		LastSucceededAt      string      `json:"last_succeeded_at"`
-		ErrorID              int         `json:"error_id"`
-		Status               int         `json:"status"`
-		Type                 AccountType `json:"type"`
-		AccountIDHash        string      `json:"account_id_hash"`
-		ShowPath             MFShowPath  `json:"show_path"`
-		AggregationQueuePath MFPath      `json:"aggregation_queue_path"`
-		ServiceID            int         `json:"service_id"`
-		ServiceType          string      `json:"service_type"`
-		ServiceCategoryID    int         `json:"service_category_id"`
-		SubAccounts          []struct {
-			SubAccountIDHash      string `json:"sub_account_id_hash"`
-			SubName               string `json:"sub_name"`
-			SubType               string `json:"sub_type"`
-			SubNumber             string `json:"sub_number"`
-			UserAssetDetSummaries []struct {
-				AssetClassID      int     `json:"asset_class_id"`
-				AssetSubclassID   int     `json:"asset_subclass_id"`
-				AssetSubclassName string  `json:"asset_subclass_name"`
-				AssetSubclassUnit string  `json:"asset_subclass_unit"`
-				Value             float64 `json:"value"`
-				JPYValue          float64 `json:"jpyvalue"`
-			} `json:"user_asset_det_summaries"`
-		} `json:"sub_accounts"`
-	} `json:"accounts"`
+	Accounts []AccountSummary `json:"accounts"`
+}
+
+// AccountSummary is the summary of a linked account
+type AccountSummary struct {
+	Name                 string              `json:"name"`
+	Amount               Money               `json:"amount"`
+	LastLoginAt          Timestamp           `json:"last_login_at"`
+	LastAggregatedAt     Timestamp           `json:"last_aggregated_at"`
+	LastSucceededAt      Timestamp           `json:"last_succeeded_at"`
+	ErrorID              AggregationErrorID  `json:"error_id"`
+	Status               AccountStatus       `json:"status"`
+	Type                 AccountType         `json:"type"`
+	AccountIDHash        string              `json:"account_id_hash"`
+	ShowPath             MFShowPath          `json:"show_path"`
+	AggregationQueuePath MFPath              `json:"aggregation_queue_path"`
+	ServiceID            int                 `json:"service_id"`
+	ServiceType          string              `json:"service_type"`
+	ServiceCategoryID    int                 `json:"service_category_id"`
+	ColorCode            string              `json:"color_code"`
+	IsShowTransaction    bool                `json:"is_show_transaction"`
+	SubAccounts          []SubAccountSummary `json:"sub_accounts"`
+}
+
+// SubAccountSummary is the summary of a sub-account, eg a savings account of a bank
+type SubAccountSummary struct {
+	SubAccountIDHash      string            `json:"sub_account_id_hash"`
+	SubName               string            `json:"sub_name"`
+	SubType               string            `json:"sub_type"`
+	SubNumber             string            `json:"sub_number"`
+	ServiceCategoryID     StringID          `json:"service_category_id"`
+	UserAssetDetSummaries []AssetDetSummary `json:"user_asset_det_summaries"`
+}
+
+// AssetDetSummary is the value of a sub-account held in an asset subclass
+type AssetDetSummary struct {
+	AssetClassID      int    `json:"asset_class_id"`
+	AssetSubclassID   int    `json:"asset_subclass_id"`
+	AssetSubclassName string `json:"asset_subclass_name"`
+	AssetSubclassUnit string `json:"asset_subclass_unit"`
+	// Value is the amount in AssetSubclassUnit, which isn't necessarily a currency
+	Value    float64 `json:"value"`
+	JPYValue Money   `json:"jpyvalue"`
 }
 
 // TransactionsResponse represents the response from the transactions endpoint
 type TransactionsResponse struct {
 	EmptyState struct {
 		RecommendedServices struct {
-			Services []struct {
-				ID              int    `json:"id"`
-				ServiceName     string `json:"service_name"`
-				ServiceType     string `json:"service_type"`
-				ColorCode       string `json:"color_code"`
-				ServiceCategory struct {
-					ID           int    `json:"id"`
-					CategoryType string `json:"category_type"`
-				} `json:"service_category"`
-			} `json:"services"`
+			Services []RecommendedService `json:"services"`
 		} `json:"recommended_services"`
 	} `json:"empty_state"`
 }
 
+// RecommendedService is a service suggested for linking when there are no transactions yet
+type RecommendedService struct {
+	ID              int    `json:"id"`
+	ServiceName     string `json:"service_name"`
+	ServiceType     string `json:"service_type"`
+	ColorCode       string `json:"color_code"`
+	ServiceCategory struct {
+		ID           int    `json:"id"`
+		CategoryType string `json:"category_type"`
+	} `json:"service_category"`
+}
+
 // UserAssetActsResponse represents the response from the user asset acts endpoint
 type UserAssetActsResponse struct {
 	UserAssetActs  []*UserAssetAct `json:"user_asset_acts"`
@@ -149,8 +162,8 @@ type UserAssetActsResponse struct {
 	TotalCount     int             `json:"total_count"`
 	Offset         int             `json:"offset"`
 	Size           int             `json:"size"`
-	From           string          `json:"from"`
-	To             string          `json:"to"`
+	From           Date            `json:"from"`
+	To             Date            `json:"to"`
 	NewRecordCount int             `json:"new_record_count"`
 }
 
@@ -181,160 +194,268 @@ func (sid *StringID) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	return fmt.Errorf("value must be string or number, got %s", data)
+	return newUnmarshalTypeError(data, *sid)
 }
 
 // UserAssetAct represents a single user asset activity
 type UserAssetAct struct {
-	ID               StringID  `json:"id"`
-	AccountID        StringID  `json:"account_id"`
-	SubAccountID     StringID  `json:"sub_account_id"`
-	IsTransfer       bool      `json:"is_transfer"`
-	IsIncome         bool      `json:"is_income"`
-	Content          string    `json:"content"`
-	OrigContent      string    `json:"orig_content"`
-	Amount           float64   `json:"amount"`
-	OrigAmount       float64   `json:"orig_amount"`
-	Currency         string    `json:"currency"`
-	JPYRate          float64   `json:"jpyrate"`
-	LargeCategoryID  StringID  `json:"large_category_id"`
-	MiddleCategoryID StringID  `json:"middle_category_id"`
-	CreatedAt        time.Time `json:"created_at"`
-	RecognizedAt     time.Time `json:"recognized_at"`
-	UpdatedAt        string    `json:"updated_at"`
-	Account          struct {
-		ServiceID         StringID `json:"service_id"`
-		ServiceCategoryID StringID `json:"service_category_id"`
-		Service           struct {
-			ServiceName string `json:"service_name"`
-		} `json:"service"`
-	} `json:"account"`
-	SubAccount struct {
-		SubName   string `json:"sub_name"`
-		SubType   string `json:"sub_type"`
-		SubNumber string `json:"sub_number"`
-	} `json:"sub_account"`
-	IsJournalizable bool `json:"is_journalizable_service"`
-	IsJournalized   bool `json:"is_journalized"`
+	ID           StringID `json:"id"`
+	AccountID    StringID `json:"account_id"`
+	SubAccountID StringID `json:"sub_account_id"`
+	IsTransfer   bool     `json:"is_transfer"`
+	IsIncome     bool     `json:"is_income"`
+	Content      string   `json:"content"`
+	OrigContent  string   `json:"orig_content"`
+	// Amount is in JPY, OrigAmount in Currency
+	Amount           Money                  `json:"amount"`
+	OrigAmount       Money                  `json:"orig_amount"`
+	Currency         string                 `json:"currency"`
+	JPYRate          float64                `json:"jpyrate"`
+	LargeCategoryID  StringID               `json:"large_category_id"`
+	MiddleCategoryID StringID               `json:"middle_category_id"`
+	CreatedAt        Timestamp              `json:"created_at"`
+	RecognizedAt     Timestamp              `json:"recognized_at"`
+	UpdatedAt        Timestamp              `json:"updated_at"`
+	Account          UserAssetActAccount    `json:"account"`
+	SubAccount       UserAssetActSubAccount `json:"sub_account"`
+	IsJournalizable  bool                   `json:"is_journalizable_service"`
+	IsJournalized    bool                   `json:"is_journalized"`
+}
+
+func (a *UserAssetAct) UnmarshalJSON(data []byte) error {
+	// alias drops the UnmarshalJSON method to avoid recursion, OrigAmount is decoded once Currency is known
+	type alias UserAssetAct
+	aux := struct {
+		*alias
+		OrigAmount json.RawMessage `json:"orig_amount"`
+	}{alias: (*alias)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if a.Currency == "" {
+		a.Currency = DefaultCurrency
+	}
+
+	var err error
+	a.OrigAmount, err = unmarshalMoney(aux.OrigAmount, a.Currency)
+	return withField(err, "orig_amount")
+}
+
+// UserAssetActAccount is the account a user asset activity belongs to
+type UserAssetActAccount struct {
+	ServiceID         StringID `json:"service_id"`
+	ServiceCategoryID StringID `json:"service_category_id"`
+	Service           struct {
+		ServiceName string `json:"service_name"`
+	} `json:"service"`
+}
+
+// UserAssetActSubAccount is the sub-account a user asset activity belongs to
+type UserAssetActSubAccount struct {
+	SubName   string `json:"sub_name"`
+	SubType   string `json:"sub_type"`
+	SubNumber string `json:"sub_number"`
 }
 
 // AccountResponse represents the response from the account endpoint
 type AccountResponse struct {
-	Account struct {
-		ServiceID         int       `json:"service_id"`
-		Status            string    `json:"status"`
-		ErrorID           string    `json:"error_id"`
-		LastLoginAt       time.Time `json:"last_login_at"`
-		LastSucceededAt   string    `json:"last_succeeded_at"`
-		LastAggregatedAt  time.Time `json:"last_aggregated_at"`
-		AccountIDHash     string    `json:"account_id_hash"`
-		DisplayName       string    `json:"display_name"`
-		ServiceCategoryID string    `json:"service_category_id"`
-		TotalAsset        float64   `json:"total_asset"`
-		TotalLiability    float64   `json:"total_liability"`
-		SubAccounts       []struct {
-			SubAccountIDHash      string `json:"sub_account_id_hash"`
-			SubName               string `json:"sub_name"`
-			SubType               string `json:"sub_type"`
-			SubNumber             string `json:"sub_number"`
-			ServiceCategoryID     string `json:"service_category_id"`
-			UserAssetDetSummaries []struct {
-				AssetClassID      int     `json:"asset_class_id"`
-				AssetSubclassID   int     `json:"asset_subclass_id"`
-				AssetSubclassName string  `json:"asset_subclass_name"`
-				Value             float64 `json:"value"`
-				JPYValue          float64 `json:"jpyvalue"`
-			} `json:"user_asset_det_summaries"`
-		} `json:"sub_accounts"`
-		Service struct {
-			ServiceType           string `json:"service_type"`
-			LoginURL              string `json:"login_url"`
-			IsShowTransaction     bool   `json:"is_show_transaction"`
-			ColorCode             string `json:"color_code"`
-			Aggregable            bool   `json:"aggregable"`
-			RequiresUserOperation bool   `json:"requires_user_operation"`
-		} `json:"service"`
-	} `json:"account"`
+	Account Account `json:"account"`
+}
+
+// Account is a linked account with its sub-accounts
+type Account struct {
+	ServiceID         int                 `json:"service_id"`
+	Status            AccountStatus       `json:"status"`
+	ErrorID           AggregationErrorID  `json:"error_id"`
+	LastLoginAt       Timestamp           `json:"last_login_at"`
+	LastSucceededAt   Timestamp           `json:"last_succeeded_at"`
+	LastAggregatedAt  Timestamp           `json:"last_aggregated_at"`
+	AccountIDHash     string              `json:"account_id_hash"`
+	DisplayName       string              `json:"display_name"`
+	ServiceCategoryID string              `json:"service_category_id"`
+	TotalAsset        Money               `json:"total_asset"`
+	TotalLiability    Money               `json:"total_liability"`
+	SubAccounts       []SubAccountSummary `json:"sub_accounts"`
+	Service           AccountService      `json:"service"`
+}
+
+// NeedsReauth reports whether the user has to log in to the account again before it can be
+// aggregated: the last aggregation failed and either the service requires user operation
+// (eg a one-time password) or the error ID is one of the known login errors
+func (a *Account) NeedsReauth() bool {
+	return a.ErrorID.IsError() && (a.Service.RequiresUserOperation || a.ErrorID.NeedsReauth())
+}
+
+// AccountService describes the service (institution) of an account
+type AccountService struct {
+	ServiceType           string `json:"service_type"`
+	LoginURL              string `json:"login_url"`
+	IsShowTransaction     bool   `json:"is_show_transaction"`
+	ColorCode             string `json:"color_code"`
+	Aggregable            bool   `json:"aggregable"`
+	RequiresUserOperation bool   `json:"requires_user_operation"`
 }
 
 // AssetHistoryResponse represents the asset history response
 type AssetHistoryResponse struct {
-	Histories []struct {
-		Date      string  `json:"date"`
-		Amount    float64 `json:"amount"`
-		Category  string  `json:"category"`
-		ClassID   int     `json:"class_id"`
-		ClassName string  `json:"class_name"`
-	} `json:"histories"`
+	Histories []AssetHistoryEntry `json:"histories"`
+}
+
+// AssetHistoryEntry is the amount of an asset class at a date
+type AssetHistoryEntry struct {
+	Date      Date   `json:"date"`
+	Amount    Money  `json:"amount"`
+	Category  string `json:"category"`
+	ClassID   int    `json:"class_id"`
+	ClassName string `json:"class_name"`
 }
 
 // AssetClassesResponse represents the asset classes response
 type AssetClassesResponse struct {
-	AssetClasses []struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-	} `json:"asset_classes"`
+	AssetClasses []AssetClassEntry `json:"asset_classes"`
+}
+
+// AssetClassEntry is an asset class of the asset class master
+type AssetClassEntry struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 // AssetSubclassesResponse represents the asset subclasses response
 type AssetSubclassesResponse struct {
-	AssetSubclasses []struct {
-		ID       int    `json:"id"`
-		Name     string `json:"name"`
-		ClassID  int    `json:"class_id"`
-		Unit     string `json:"unit"`
-		Ordering int    `json:"ordering"`
-	} `json:"asset_subclasses"`
+	AssetSubclasses []AssetSubclassEntry `json:"asset_subclasses"`
+}
+
+// AssetSubclassEntry is an asset subclass of the asset subclass master
+type AssetSubclassEntry struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ClassID  int    `json:"class_id"`
+	Unit     string `json:"unit"`
+	Ordering int    `json:"ordering"`
+	Liquid   int    `json:"liquid"`
 }
 
 // CategoriesResponse represents the categories response
 type CategoriesResponse struct {
-	LargeCategories []struct {
-		ID               int    `json:"id"`
-		Name             string `json:"name"`
-		MiddleCategories []struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		} `json:"middle_categories"`
-	} `json:"large_categories"`
+	LargeCategories []LargeCategory `json:"large_categories"`
+}
+
+// LargeCategory is a large transaction category of the category master
+type LargeCategory struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	MiddleCategories []MiddleCategory `json:"middle_categories"`
+}
+
+// MiddleCategory is a middle transaction category of the category master
+type MiddleCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
-// UserAssetActUpdates represents the update payload for transactions
+// UserAssetActUpdates represents the update payload for transactions.
+// Zero values are not sent, fields listed in Clear are sent as null to clear them.
 type UserAssetActUpdates struct {
-	LargeCategoryID  int     `json:"large_category_id,omitempty"`
-	MiddleCategoryID int     `json:"middle_category_id,omitempty"`
-	Content          string  `json:"content,omitempty"`
-	Amount           float64 `json:"amount,omitempty"`
+	LargeCategoryID  int    `json:"large_category_id,omitempty"`
+	MiddleCategoryID int    `json:"middle_category_id,omitempty"`
+	Content          string `json:"content,omitempty"`
+	// Amount is the new amount in JPY. Nil leaves the amount unchanged, a pointer to a zero
+	// amount sets it to 0 and UserAssetActFieldAmount in Clear sends null.
+	Amount *Money `json:"amount,omitempty"`
+	// Date moves the transaction to another day, the zero time leaves it unchanged
+	Date time.Time `json:"-"`
+	Memo string    `json:"memo,omitempty"`
+	// IsIncome switches a manual transaction between income and expense, nil leaves it unchanged
+	IsIncome *bool `json:"is_income,omitempty"`
+	// SubAccountIDHash moves a manual transaction to another sub-account (eg a wallet)
+	SubAccountIDHash string              `json:"sub_account_id_hash,omitempty"`
+	Clear            []UserAssetActField `json:"-"`
+}
+
+// UserAssetActField is the JSON name of an updatable UserAssetAct field
+type UserAssetActField string
+
+const (
+	UserAssetActFieldLargeCategoryID  UserAssetActField = "large_category_id"
+	UserAssetActFieldMiddleCategoryID UserAssetActField = "middle_category_id"
+	UserAssetActFieldContent          UserAssetActField = "content"
+	UserAssetActFieldAmount           UserAssetActField = "amount"
+	UserAssetActFieldDate             UserAssetActField = "recognized_at"
+	UserAssetActFieldMemo             UserAssetActField = "memo"
+	UserAssetActFieldIsIncome         UserAssetActField = "is_income"
+	UserAssetActFieldSubAccountIDHash UserAssetActField = "sub_account_id_hash"
+)
+
+func (u UserAssetActUpdates) MarshalJSON() ([]byte, error) {
+	// the API takes the amount as a bare number in yen
+	if u.Amount != nil && u.Amount.Currency != "" && u.Amount.Currency != "JPY" {
+		return nil, fmt.Errorf("%w: amount must be in JPY, got %s", ErrCurrencyMismatch, u.Amount.Currency)
+	}
+
+	// alias drops the MarshalJSON method to avoid recursion
+	type alias UserAssetActUpdates
+	data, err := json.Marshal(alias(u))
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Clear) == 0 && u.Date.IsZero() {
+		return data, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if !u.Date.IsZero() {
+		// same format as the date of a new manual transaction
+		date, err := json.Marshal(u.Date.In(tokyo).Format(cashFlowDateLayout))
+		if err != nil {
+			return nil, err
+		}
+		fields[string(UserAssetActFieldDate)] = date
+	}
+	for _, field := range u.Clear {
+		fields[string(field)] = json.RawMessage("null")
+	}
+
+	return json.Marshal(fields)
 }
 
 // ServiceCategoriesResponse represents the service categories response
 type ServiceCategoriesResponse struct {
-	Categories []struct {
-		ID          int    `json:"id"`
-		Name        string `json:"name"`
-		ServiceType string `json:"service_type"`
-	} `json:"categories"`
+	Categories []ServiceCategory `json:"categories"`
+}
+
+// ServiceCategory is a category of linkable services, eg banks or cards
+type ServiceCategory struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ServiceType string `json:"service_type"`
 }
 
 // ServicesResponse represents the services response
 type ServicesResponse struct {
-	Services []struct {
-		ID          int    `json:"id"`
-		Name        string `json:"name"`
-		ServiceType string `json:"service_type"`
-		CategoryID  int    `json:"category_id"`
-	} `json:"services"`
+	Services []Service `json:"services"`
+}
+
+// Service represents a linkable institution such as a bank, card or broker
+type Service struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Yomigana    string `json:"yomigana"`
+	ServiceType string `json:"service_type"`
+	CategoryID  int    `json:"category_id"`
 }
 
 // CashFlowTermDataResponse represents the response from the cash flow term data endpoint
 type CashFlowTermDataResponse struct {
-	Result        string `json:"result"`
-	UserAssetActs []struct {
-		UserAssetAct UserAssetAct `json:"user_asset_act"`
-	} `json:"user_asset_acts"`
+	Result        string                 `json:"result"`
+	UserAssetActs []UserAssetActResponse `json:"user_asset_acts"`
 }
 
-// AccountDetailResponse represents the detailed account information
+// SubAccount represents a sub-account in the detailed account information
 type SubAccount struct {
 	ID               int    `json:"id"`
 	UserID           int    `json:"user_id"`
@@ -345,7 +466,7 @@ type SubAccount struct {
 	DispName         string `json:"disp_name"`
 	SubAccountIDHash string `json:"sub_account_id_hash"`
 	IsDummy          bool   `json:"is_dummy"`
-	SumValue         int    `json:"sum_value"`
+	SumValue         Money  `json:"sum_value"`
 	CurrentGroup     bool   `json:"current_group"`
 }
 
@@ -354,21 +475,22 @@ type PrevSubAccount struct {
 }
 
 type UserAssetDet struct {
-	Code              string            `json:"code"`
-	Name              string            `json:"name"`
-	Qty               float64           `json:"qty"`
-	EntriedPrice      float64           `json:"entried_price"`
-	CurrentPrice      float64           `json:"current_price"`
-	Value             float64           `json:"value"`
-	Profit            float64           `json:"profit"`
-	EntriedAt         time.Time         `json:"entried_at"`
-	ExpireAt          time.Time         `json:"expire_at"`
-	Cost              float64           `json:"cost"`
+	Code         string  `json:"code"`
+	Name         string  `json:"name"`
+	Qty          float64 `json:"qty"`
+	EntriedPrice float64 `json:"entried_price"`
+	CurrentPrice float64 `json:"current_price"`
+	// Value, Profit and Cost are in Currency
+	Value             Money             `json:"value"`
+	Profit            Money             `json:"profit"`
+	EntriedAt         Timestamp         `json:"entried_at"`
+	ExpireAt          Timestamp         `json:"expire_at"`
+	Cost              Money             `json:"cost"`
 	Currency          string            `json:"currency"`
 	JPYRate           float64           `json:"jpyrate"`
 	Interest          float64           `json:"interest"`
-	CreatedAt         time.Time         `json:"created_at"`
-	UpdatedAt         time.Time         `json:"updated_at"`
+	CreatedAt         Timestamp         `json:"created_at"`
+	UpdatedAt         Timestamp         `json:"updated_at"`
 	Extra             string            `json:"extra"`
 	AssetDetailIDHash string            `json:"asset_detail_id_hash"`
 	AccountName       string            `json:"account_name"`
@@ -382,45 +504,73 @@ type UserAssetDet struct {
 	AssetSubclass     AssetSubclassInfo `json:"asset_subclass"`
 }
 
+func (d *UserAssetDet) UnmarshalJSON(data []byte) error {
+	// alias drops the UnmarshalJSON method to avoid recursion, amounts are decoded once Currency is known
+	type alias UserAssetDet
+	aux := struct {
+		*alias
+		Value  json.RawMessage `json:"value"`
+		Profit json.RawMessage `json:"profit"`
+		Cost   json.RawMessage `json:"cost"`
+	}{alias: (*alias)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if d.Currency == "" {
+		d.Currency = DefaultCurrency
+	}
+
+	var err error
+	if d.Value, err = unmarshalMoney(aux.Value, d.Currency); err != nil {
+		return withField(err, "value")
+	}
+	if d.Profit, err = unmarshalMoney(aux.Profit, d.Currency); err != nil {
+		return withField(err, "profit")
+	}
+	d.Cost, err = unmarshalMoney(aux.Cost, d.Currency)
+	return withField(err, "cost")
+}
+
 type AccountInfo struct {
 	Account AccountDetails `json:"account"`
 }
 
 type AccountDetails struct {
-	ID                      int         `json:"id"`
-	UserID                  int         `json:"user_id"`
-	ServiceID               int         `json:"service_id"`
-	UserServiceID           int         `json:"user_service_id"`
-	ServiceCategoryID       int         `json:"service_category_id"`
-	ManualFlag              int         `json:"manual_flag"`
-	Status                  int         `json:"status"`
-	ErrorID                 string      `json:"error_id"`
-	DispName                string      `json:"disp_name"`
-	Memo                    string      `json:"memo"`
-	MsgFlag                 int         `json:"msg_flag"`
-	MsgTime                 time.Time   `json:"msg_time"`
-	AccountUID              string      `json:"account_uid"`
-	AccountUIDHidden        string      `json:"account_uid_hidden"`
-	CheckKey                string      `json:"check_key"`
-	LastLoginAt             time.Time   `json:"last_login_at"`
-	FirstSucceededAt        time.Time   `json:"first_succeeded_at"`
-	LastSucceededAt         string      `json:"last_succeeded_at"`
-	OriginalLastSucceededAt time.Time   `json:"original_last_succeeded_at"`
-	LastAggregatedAt        time.Time   `json:"last_aggregated_at"`
-	NextAggregateAt         time.Time   `json:"next_aggregate_at"`
-	AggreSpan               int         `json:"aggre_span"`
-	AggreStartDate          time.Time   `json:"aggre_start_date"`
-	Message                 string      `json:"message"`
-	AssistAccountID         int         `json:"assist_account_id"`
-	AssistSubAccountID      int         `json:"assist_sub_account_id"`
-	AssistTargetDetID       int         `json:"assist_target_det_id"`
-	OverrideProxyTag        string      `json:"override_proxy_tag"`
-	IsDemo                  bool        `json:"is_demo"`
-	IsSuspended             bool        `json:"is_suspended"`
-	CreatedAt               time.Time   `json:"created_at"`
-	Withdrawal              int         `json:"withdrawal"`
-	DeletedAt               time.Time   `json:"deleted_at"`
-	Service                 ServiceInfo `json:"service"`
+	ID                      int                `json:"id"`
+	UserID                  int                `json:"user_id"`
+	ServiceID               int                `json:"service_id"`
+	UserServiceID           int                `json:"user_service_id"`
+	ServiceCategoryID       int                `json:"service_category_id"`
+	ManualFlag              int                `json:"manual_flag"`
+	Status                  AccountStatus      `json:"status"`
+	ErrorID                 AggregationErrorID `json:"error_id"`
+	DispName                string             `json:"disp_name"`
+	Memo                    string             `json:"memo"`
+	MsgFlag                 int                `json:"msg_flag"`
+	MsgTime                 Timestamp          `json:"msg_time"`
+	AccountUID              string             `json:"account_uid"`
+	AccountUIDHidden        string             `json:"account_uid_hidden"`
+	CheckKey                string             `json:"check_key"`
+	LastLoginAt             Timestamp          `json:"last_login_at"`
+	FirstSucceededAt        Timestamp          `json:"first_succeeded_at"`
+	LastSucceededAt         Timestamp          `json:"last_succeeded_at"`
+	OriginalLastSucceededAt Timestamp          `json:"original_last_succeeded_at"`
+	LastAggregatedAt        Timestamp          `json:"last_aggregated_at"`
+	NextAggregateAt         Timestamp          `json:"next_aggregate_at"`
+	AggreSpan               int                `json:"aggre_span"`
+	AggreStartDate          Timestamp          `json:"aggre_start_date"`
+	Message                 string             `json:"message"`
+	AssistAccountID         int                `json:"assist_account_id"`
+	AssistSubAccountID      int                `json:"assist_sub_account_id"`
+	AssistTargetDetID       int                `json:"assist_target_det_id"`
+	OverrideProxyTag        string             `json:"override_proxy_tag"`
+	IsDemo                  bool               `json:"is_demo"`
+	IsSuspended             bool               `json:"is_suspended"`
+	CreatedAt               Timestamp          `json:"created_at"`
+	Withdrawal              int                `json:"withdrawal"`
+	DeletedAt               Timestamp          `json:"deleted_at"`
+	Service                 ServiceInfo        `json:"service"`
 }
 
 type ServiceInfo struct {
@@ -506,12 +656,12 @@ const (
 
 type AccountDetailInformation struct {
 	PrevSubAccounts    []*PrevSubAccount             `json:"prev_sub_accounts"`
-	AssetTotalLia      int                           `json:"asset_total_lia"`
+	AssetTotalLia      Money                         `json:"asset_total_lia"`
 	DispSumHistory     map[string][]int              `json:"disp_sum_history"`
-	FromDate           string                        `json:"from_date"`
-	ToDate             string                        `json:"to_date"`
-	UserAssetClassSums map[string]int                `json:"user_asset_class_sums"`
-	AssetTotalAsset    int                           `json:"asset_total_asset"`
+	FromDate           Date                          `json:"from_date"`
+	ToDate             Date                          `json:"to_date"`
+	UserAssetClassSums map[string]Money              `json:"user_asset_class_sums"`
+	AssetTotalAsset    Money                         `json:"asset_total_asset"`
 	UserAssetDets      map[AssetType][]*UserAssetDet `json:"user_asset_dets"`
 	UserAssetActs      any                           `json:"user_asset_acts"`
 }
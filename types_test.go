@@ -0,0 +1,68 @@
+package moneyforward
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUserAssetActUpdatesMarshalJSON(t *testing.T) {
+	zero := JPY(0)
+	yen := JPY(500)
+	usd := NewMoney(1234, "USD")
+	income, expense := true, false
+
+	tests := []struct {
+		name    string
+		updates UserAssetActUpdates
+		want    string
+		wantErr error
+	}{
+		{"empty", UserAssetActUpdates{}, `{}`, nil},
+		{"fields", UserAssetActUpdates{LargeCategoryID: 11, Content: "lunch"}, `{"content":"lunch","large_category_id":11}`, nil},
+		{"amount", UserAssetActUpdates{Amount: &yen}, `{"amount":500}`, nil},
+		{"zero amount", UserAssetActUpdates{Amount: &zero}, `{"amount":0}`, nil},
+		{"clear", UserAssetActUpdates{Content: "x", Clear: []UserAssetActField{UserAssetActFieldAmount, UserAssetActFieldMiddleCategoryID}}, `{"amount":null,"content":"x","middle_category_id":null}`, nil},
+		{"manual transaction fields", UserAssetActUpdates{Date: time.Date(2024, 1, 14, 16, 0, 0, 0, time.UTC), Memo: "cash", IsIncome: &income, SubAccountIDHash: "wallet"}, `{"is_income":true,"memo":"cash","recognized_at":"2024/01/15","sub_account_id_hash":"wallet"}`, nil},
+		{"switch to expense", UserAssetActUpdates{IsIncome: &expense}, `{"is_income":false}`, nil},
+		{"clear memo", UserAssetActUpdates{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo), Clear: []UserAssetActField{UserAssetActFieldMemo}}, `{"memo":null,"recognized_at":"2024/01/15"}`, nil},
+		{"foreign currency", UserAssetActUpdates{Amount: &usd}, ``, ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.updates)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// compare decoded to ignore key order
+			var got, want map[string]interface{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if string(mustMarshal(t, got)) != string(mustMarshal(t, want)) {
+				t.Errorf("got %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,151 @@
+package moneyforward
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// AllUserAssetActivities iterates over all user asset activities matching params,
+// requesting pages of params.Size starting at params.Offset until TotalCount is reached.
+// Iteration stops after the first error, which is yielded with a nil activity.
+func (c *Client) AllUserAssetActivities(ctx context.Context, params UserAssetActsParams, opts ...RequestOption) iter.Seq2[*UserAssetAct, error] {
+	return func(yield func(*UserAssetAct, error) bool) {
+		for {
+			resp, err := c.GetUserAssetActivities(ctx, params, opts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, act := range resp.UserAssetActs {
+				if !yield(act, nil) {
+					return
+				}
+			}
+
+			params.Offset += len(resp.UserAssetActs)
+			if len(resp.UserAssetActs) == 0 || params.Offset >= resp.TotalCount {
+				return
+			}
+		}
+	}
+}
+
+// UpdateUserAssetAct updates a user asset activity and returns the updated activity
+func (c *Client) UpdateUserAssetAct(ctx context.Context, activityID string, updates UserAssetActUpdates, opts ...RequestOption) (*UserAssetAct, error) {
+	return c.putUserAssetAct(ctx, activityID, updates, opts...)
+}
+
+// putUserAssetAct sends fields as the user_asset_act of an update request
+func (c *Client) putUserAssetAct(ctx context.Context, activityID string, fields interface{}, opts ...RequestOption) (*UserAssetAct, error) {
+	body := map[string]interface{}{
+		"user_asset_act": fields,
+	}
+	req, err := c.newJSONRequest(ctx, "PUT", fmt.Sprintf("/sp2/user_asset_acts/%s", activityID), body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp UserAssetActResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.UserAssetAct, nil
+}
+
+// ManualTransaction is a transaction recorded by hand, eg cash spending
+type ManualTransaction struct {
+	// SubAccountIDHash is the sub-account (eg a wallet) the transaction is recorded on
+	SubAccountIDHash string
+	Date             time.Time
+	// Amount is the absolute amount in JPY, the direction is given by IsIncome
+	Amount           Money
+	IsIncome         bool
+	LargeCategoryID  int
+	MiddleCategoryID int
+	Content          string
+	Memo             string
+}
+
+type manualTransactionBody struct {
+	SubAccountIDHash string `json:"sub_account_id_hash"`
+	RecognizedAt     string `json:"recognized_at"`
+	Amount           Money  `json:"amount"`
+	IsIncome         bool   `json:"is_income"`
+	LargeCategoryID  int    `json:"large_category_id"`
+	MiddleCategoryID int    `json:"middle_category_id,omitempty"`
+	Content          string `json:"content,omitempty"`
+	Memo             string `json:"memo,omitempty"`
+}
+
+// validate checks the transaction for missing or invalid values
+func (t ManualTransaction) validate() error {
+	switch {
+	case t.SubAccountIDHash == "":
+		return fmt.Errorf("sub account is required")
+	case t.Date.IsZero():
+		return fmt.Errorf("date is required")
+	case !t.Amount.IsPositive():
+		return fmt.Errorf("amount must be positive, got %s", t.Amount)
+	case t.Amount.Currency != "" && t.Amount.Currency != "JPY":
+		// the API takes the amount as a bare number in yen
+		return fmt.Errorf("%w: amount must be in JPY, got %s", ErrCurrencyMismatch, t.Amount.Currency)
+	case t.LargeCategoryID == 0:
+		return fmt.Errorf("large category is required")
+	}
+	return nil
+}
+
+// CreateManualTransaction records a manual transaction and returns the created activity.
+// The categories are validated against the category tree before the request is sent.
+func (c *Client) CreateManualTransaction(ctx context.Context, t ManualTransaction, opts ...RequestOption) (*UserAssetAct, error) {
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manual transaction: %w", err)
+	}
+
+	// opts are meant for the POST, the tree is cached for the lifetime of the client
+	tree, err := c.CategoryTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	if err := tree.Validate(t.LargeCategoryID, t.MiddleCategoryID); err != nil {
+		return nil, fmt.Errorf("invalid manual transaction: %w", err)
+	}
+
+	body := map[string]manualTransactionBody{
+		"user_asset_act": {
+			SubAccountIDHash: t.SubAccountIDHash,
+			RecognizedAt:     t.Date.In(tokyo).Format(cashFlowDateLayout),
+			Amount:           t.Amount,
+			IsIncome:         t.IsIncome,
+			LargeCategoryID:  t.LargeCategoryID,
+			MiddleCategoryID: t.MiddleCategoryID,
+			Content:          t.Content,
+			Memo:             t.Memo,
+		},
+	}
+	req, err := c.newJSONRequest(ctx, "POST", "/sp2/user_asset_acts", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp UserAssetActResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.UserAssetAct, nil
+}
+
+// DeleteUserAssetAct deletes a user asset activity
+func (c *Client) DeleteUserAssetAct(ctx context.Context, activityID string, opts ...RequestOption) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/sp2/user_asset_acts/%s", activityID), opts...)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
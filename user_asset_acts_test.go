@@ -0,0 +1,140 @@
+package moneyforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManualTransactionValidate(t *testing.T) {
+	valid := ManualTransaction{
+		SubAccountIDHash: "abc",
+		Date:             time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo),
+		Amount:           JPY(1234),
+		LargeCategoryID:  11,
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(*ManualTransaction)
+		wantErr bool
+		is      error
+	}{
+		{"valid", func(*ManualTransaction) {}, false, nil},
+		{"amount without currency", func(t *ManualTransaction) { t.Amount = Money{Amount: 1234} }, false, nil},
+		{"missing sub account", func(t *ManualTransaction) { t.SubAccountIDHash = "" }, true, nil},
+		{"missing date", func(t *ManualTransaction) { t.Date = time.Time{} }, true, nil},
+		{"zero amount", func(t *ManualTransaction) { t.Amount = JPY(0) }, true, nil},
+		{"negative amount", func(t *ManualTransaction) { t.Amount = JPY(-1) }, true, nil},
+		{"foreign currency", func(t *ManualTransaction) { t.Amount = NewMoney(1234, "USD") }, true, ErrCurrencyMismatch},
+		{"missing category", func(t *ManualTransaction) { t.LargeCategoryID = 0 }, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := valid
+			tt.edit(&tx)
+			err := tx.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("validate() = %v, want %v", err, tt.is)
+			}
+		})
+	}
+}
+
+// userAssetActsServer serves total activities in pages, recording the query of every request.
+// Pages beyond served are empty, failAt makes the request with that index fail.
+func userAssetActsServer(total, served, failAt int, queries *[]url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		*queries = append(*queries, q)
+		if len(*queries)-1 == failAt {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		size, _ := strconv.Atoi(q.Get("size"))
+		var acts []string
+		for id := offset; id < min(offset+size, served); id++ {
+			acts = append(acts, fmt.Sprintf(`{"id":%d}`, id))
+		}
+		fmt.Fprintf(w, `{"user_asset_acts":[%s],"total_count":%d}`, strings.Join(acts, ","), total)
+	}
+}
+
+func TestAllUserAssetActivities(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       UserAssetActsParams
+		total        int
+		served       int
+		failAt       int
+		stopAfter    int
+		wantIDs      int
+		wantRequests int
+		wantErr      bool
+	}{
+		{"single page", UserAssetActsParams{Size: 10, IsNew: true}, 4, 4, -1, 0, 4, 1, false},
+		{"several pages", UserAssetActsParams{Size: 3, IsNew: true}, 8, 8, -1, 0, 8, 3, false},
+		{"exact pages", UserAssetActsParams{Size: 4}, 8, 8, -1, 0, 8, 2, false},
+		{"starts at offset", UserAssetActsParams{Size: 4, Offset: 5}, 8, 8, -1, 0, 3, 1, false},
+		{"empty", UserAssetActsParams{Size: 4}, 0, 0, -1, 0, 0, 1, false},
+		{"stops on empty page", UserAssetActsParams{Size: 4}, 100, 6, -1, 0, 6, 3, false},
+		{"early break", UserAssetActsParams{Size: 3}, 8, 8, -1, 4, 4, 2, false},
+		{"error", UserAssetActsParams{Size: 3}, 8, 8, 1, 0, 3, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []url.Values
+			c := newTestClient(t, userAssetActsServer(tt.total, tt.served, tt.failAt, &queries))
+
+			var ids []string
+			var errs []error
+			for act, err := range c.AllUserAssetActivities(context.Background(), tt.params) {
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				ids = append(ids, string(act.ID))
+				if len(ids) == tt.stopAfter {
+					break
+				}
+			}
+
+			if len(ids) != tt.wantIDs {
+				t.Errorf("got %d activities, want %d", len(ids), tt.wantIDs)
+			}
+			for i, id := range ids {
+				if want := strconv.Itoa(tt.params.Offset + i); id != want {
+					t.Errorf("activity %d has ID %s, want %s", i, id, want)
+				}
+			}
+			if len(queries) != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", len(queries), tt.wantRequests)
+			}
+			if tt.wantErr != (len(errs) == 1) || len(errs) > 1 {
+				t.Errorf("errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+
+			// filters are sent with every page
+			for _, q := range queries {
+				if q.Get("is_new") != strconv.Itoa(boolToInt(tt.params.IsNew)) ||
+					q.Get("is_old") != strconv.Itoa(boolToInt(tt.params.IsOld)) ||
+					q.Get("is_continuous") != strconv.Itoa(boolToInt(tt.params.IsContinuous)) {
+					t.Errorf("query = %v, want the filters of %+v", q, tt.params)
+				}
+			}
+		})
+	}
+}